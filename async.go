@@ -0,0 +1,234 @@
+package excelizeam
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+var (
+	ErrInvalidAsyncLimit = errors.New("invalid async limit")
+)
+
+// DefaultAsyncBatchSize Default number of asynchronous operations handed to a worker at once
+const DefaultAsyncBatchSize = 256
+
+// DefaultAsyncErrorLimit Default number of asynchronous operation errors returned by Wait
+const DefaultAsyncErrorLimit = 1
+
+// DefaultAsyncWorkers Default number of workers running asynchronous operations
+func DefaultAsyncWorkers() int {
+	return runtime.GOMAXPROCS(0)
+}
+
+// asyncQueue Bounded worker pool for asynchronous operations
+// Operations are queued and handed to workers in batches of batchSize.
+// Once all workers are busy, handing over a batch blocks until a worker is free.
+// Every operation gets a sequence number in the order it was queued,
+// and settle is called with the last sequence number up to which every operation has finished,
+// so that the results of the operations can be applied in the order they were called.
+// A worker settles without waiting after each batch, and Wait settles the rest.
+// A failed operation does not stop the others; its error is collected up to errLimit.
+// Go and Wait may be called concurrently from any goroutine.
+type asyncQueue struct {
+	ctx    context.Context
+	settle func(lastSeq uint64, wait bool) error
+
+	mu        sync.Mutex
+	workers   chan struct{}
+	batchSize int
+	seq       uint64
+	pending   []asyncOp
+	// doneSeq every operation up to this sequence number has finished
+	doneSeq uint64
+	// doneBatches last sequence numbers of finished batches keyed by their first one, which are after doneSeq
+	doneBatches map[uint64]uint64
+	done        *sync.Cond
+	ctxErr      error
+	errLimit    int
+	errs        []asyncError
+}
+
+type asyncOp struct {
+	seq uint64
+	fn  func(seq uint64) error
+}
+
+type asyncError struct {
+	seq uint64
+	err error
+}
+
+func newAsyncQueue(ctx context.Context, workers, batchSize int, settle func(lastSeq uint64, wait bool) error) *asyncQueue {
+	q := &asyncQueue{
+		ctx:         ctx,
+		settle:      settle,
+		doneBatches: make(map[uint64]uint64),
+		errLimit:    DefaultAsyncErrorLimit,
+	}
+	q.done = sync.NewCond(&q.mu)
+	q.setLimit(workers, batchSize)
+	return q
+}
+
+// setLimit Set the number of workers and the batch size
+// Batches already handed over keep running on the workers of the previous limit.
+func (q *asyncQueue) setLimit(workers, batchSize int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.workers = make(chan struct{}, workers)
+	q.batchSize = batchSize
+}
+
+// setErrorLimit Set the number of errors kept, 0 keeps every error
+func (q *asyncQueue) setErrorLimit(limit int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.errLimit = limit
+}
+
+// lastSeq Get the sequence number of the operation queued last
+func (q *asyncQueue) lastSeq() uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.seq
+}
+
+// Go Queue fn to be run by a worker with the sequence number of the operation
+func (q *asyncQueue) Go(fn func(seq uint64) error) {
+	q.mu.Lock()
+	q.seq++
+	q.pending = append(q.pending, asyncOp{seq: q.seq, fn: fn})
+	if len(q.pending) < q.batchSize {
+		q.mu.Unlock()
+		return
+	}
+	batch := q.pending
+	q.pending = make([]asyncOp, 0, q.batchSize)
+	q.mu.Unlock()
+
+	q.dispatch(batch)
+}
+
+// dispatch Hand the batch over to a worker, blocking until one is free
+func (q *asyncQueue) dispatch(batch []asyncOp) {
+	q.mu.Lock()
+	workers := q.workers
+	q.mu.Unlock()
+	workers <- struct{}{}
+	go func() {
+		// the worker is released after settling, so that queuing is held back while the results pile up
+		defer func() { <-workers }()
+		q.run(batch)
+		doneSeq := q.finish(batch[0].seq, batch[len(batch)-1].seq)
+		if q.settle != nil {
+			if err := q.settle(doneSeq, false); err != nil {
+				q.setCtxErr(err)
+			}
+		}
+	}()
+}
+
+// run Run the operations of the batch until ctx is done
+func (q *asyncQueue) run(batch []asyncOp) {
+	for _, op := range batch {
+		if err := q.ctx.Err(); err != nil {
+			q.setCtxErr(err)
+			return
+		}
+		if err := op.fn(op.seq); err != nil {
+			q.addError(op.seq, err)
+		}
+	}
+}
+
+// setCtxErr Keep the first error stopping the queue
+func (q *asyncQueue) setCtxErr(err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.ctxErr == nil {
+		q.ctxErr = err
+	}
+}
+
+// finish Mark the operations from firstSeq to lastSeq as finished and get the new doneSeq
+func (q *asyncQueue) finish(firstSeq, lastSeq uint64) uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.doneBatches[firstSeq] = lastSeq
+	for {
+		last, ok := q.doneBatches[q.doneSeq+1]
+		if !ok {
+			break
+		}
+		delete(q.doneBatches, q.doneSeq+1)
+		q.doneSeq = last
+	}
+	q.done.Broadcast()
+	return q.doneSeq
+}
+
+// addError Collect the error of the operation with the sequence number
+func (q *asyncQueue) addError(seq uint64, err error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.errs = append(q.errs, asyncError{seq: seq, err: err})
+	if q.errLimit > 0 && len(q.errs) >= q.errLimit*2 {
+		q.errs = q.sortedErrors()
+	}
+}
+
+// sortedErrors Get the errors in the order of the operations, at most errLimit of them
+// q.mu must be held by the caller.
+func (q *asyncQueue) sortedErrors() []asyncError {
+	errs := append([]asyncError(nil), q.errs...)
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].seq < errs[j].seq
+	})
+	if q.errLimit > 0 && len(errs) > q.errLimit {
+		errs = errs[:q.errLimit]
+	}
+	return errs
+}
+
+// Wait Hand over the queued operations, wait for all of them to finish and settle their results
+// The collected errors are returned in the order of the operations joined by errors.Join,
+// or as is when there is only one.
+func (q *asyncQueue) Wait() error {
+	q.mu.Lock()
+	batch := q.pending
+	q.pending = nil
+	lastSeq := q.seq
+	q.mu.Unlock()
+
+	if len(batch) > 0 {
+		q.dispatch(batch)
+	}
+
+	q.mu.Lock()
+	for q.doneSeq < lastSeq {
+		q.done.Wait()
+	}
+	err := q.ctxErr
+	q.mu.Unlock()
+	if err == nil && q.settle != nil {
+		err = q.settle(lastSeq, true)
+	}
+
+	q.mu.Lock()
+	asyncErrs := q.sortedErrors()
+	q.mu.Unlock()
+	errs := make([]error, 0, len(asyncErrs)+1)
+	for _, asyncErr := range asyncErrs {
+		errs = append(errs, asyncErr.err)
+	}
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,376 @@
+package excelizeam
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// cellChunkRows Number of rows held by a single cellChunk
+const cellChunkRows = 64
+
+// cellStore Row-chunked cell storage keyed by integer coordinates
+// Rows are grouped into chunks of cellChunkRows so that the map only grows with the number of chunks,
+// and each row is a dense slice indexed by column.
+type cellStore struct {
+	mu     sync.RWMutex
+	chunks map[int]*cellChunk
+	// pendingChunks indexes of the chunks holding pending updates
+	pendingChunks map[int]struct{}
+	// releasedRow rows up to this index are flushed or being flushed, so they can no longer be updated
+	releasedRow atomic.Int64
+
+	// spans pending updates of the operations spanning more than one chunk keyed by sequence number, guarded by spanMu
+	spanMu    sync.Mutex
+	spans     map[uint64]*pendingSpan
+	spanCount atomic.Int64
+}
+
+// pendingSpan Chunks holding the pending updates of an operation spanning more than one chunk
+// The chunks apply the updates of the operation in row order as the operation would have,
+// so that the updates after a failed one are skipped in every chunk.
+type pendingSpan struct {
+	// chunks indexes of the chunks whose updates of the operation are not applied yet in row order
+	chunks []int
+	failed bool
+	// changed is closed when chunks or failed changes
+	changed chan struct{}
+}
+
+type cellChunk struct {
+	idx  int
+	mu   sync.Mutex
+	rows [cellChunkRows][]storedCell
+
+	// pending updates of asynchronous operations not applied yet
+	pending []pendingUpdate
+	// applyMu is held while the pending updates taken from the chunk are applied,
+	// so that the updates of the chunk are applied in the order of the operations
+	applyMu sync.Mutex
+}
+
+type storedCell struct {
+	Cell
+	stored bool
+}
+
+type pendingUpdate struct {
+	seq uint64
+	cellUpdate
+}
+
+func chunkIndex(rowIndex int) (chunkIdx, rowOffset int) {
+	return (rowIndex - 1) / cellChunkRows, (rowIndex - 1) % cellChunkRows
+}
+
+func (s *cellStore) getChunk(chunkIdx int, create bool) *cellChunk {
+	s.mu.RLock()
+	chunk, ok := s.chunks[chunkIdx]
+	s.mu.RUnlock()
+	if ok || !create {
+		return chunk
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if chunk, ok = s.chunks[chunkIdx]; ok {
+		return chunk
+	}
+	if s.chunks == nil {
+		s.chunks = make(map[int]*cellChunk)
+	}
+	chunk = &cellChunk{idx: chunkIdx}
+	s.chunks[chunkIdx] = chunk
+	return chunk
+}
+
+// ReleaseRows Stop the rows up to rowIndex from being updated, which must be called before they are flushed
+func (s *cellStore) ReleaseRows(rowIndex int) {
+	s.releasedRow.Store(int64(rowIndex))
+}
+
+// checkReleased Get RowFlushedError when the row has been released
+// It is checked again under the lock of the chunk, since the rows may be released after the operation has checked them.
+func (s *cellStore) checkReleased(rowIndex int) error {
+	if releasedRow := int(s.releasedRow.Load()); rowIndex <= releasedRow {
+		return &RowFlushedError{RowIndex: rowIndex, FlushedRowIndex: releasedRow}
+	}
+	return nil
+}
+
+// Update Call fn with the cell at the coordinates while holding the lock of its chunk
+// exists reports whether the cell has been stored before.
+// Changes made by fn are stored only when fn returns nil, and RowFlushedError is returned once the row has been released.
+func (s *cellStore) Update(colIndex, rowIndex int, fn func(cell *Cell, exists bool) error) error {
+	if err := s.checkReleased(rowIndex); err != nil {
+		return err
+	}
+	chunkIdx, rowOffset := chunkIndex(rowIndex)
+	chunk := s.getChunk(chunkIdx, true)
+
+	chunk.mu.Lock()
+	defer chunk.mu.Unlock()
+	if err := s.checkReleased(rowIndex); err != nil {
+		return err
+	}
+	row := chunk.rows[rowOffset]
+	if len(row) < colIndex {
+		row = append(row, make([]storedCell, colIndex-len(row))...)
+		chunk.rows[rowOffset] = row
+	}
+	sc := &row[colIndex-1]
+	cell := sc.Cell
+	if err := fn(&cell, sc.stored); err != nil {
+		return err
+	}
+	sc.Cell = cell
+	sc.stored = true
+	return nil
+}
+
+// Load Get the cell at the coordinates
+func (s *cellStore) Load(colIndex, rowIndex int) (Cell, bool) {
+	chunkIdx, rowOffset := chunkIndex(rowIndex)
+	chunk := s.getChunk(chunkIdx, false)
+	if chunk == nil {
+		return Cell{}, false
+	}
+
+	chunk.mu.Lock()
+	defer chunk.mu.Unlock()
+	row := chunk.rows[rowOffset]
+	if len(row) < colIndex || !row[colIndex-1].stored {
+		return Cell{}, false
+	}
+	return row[colIndex-1].Cell, true
+}
+
+// RangeRow Call fn for each stored cell of the row in column order
+func (s *cellStore) RangeRow(rowIndex int, fn func(colIndex int, cell Cell)) {
+	chunkIdx, rowOffset := chunkIndex(rowIndex)
+	chunk := s.getChunk(chunkIdx, false)
+	if chunk == nil {
+		return
+	}
+
+	chunk.mu.Lock()
+	defer chunk.mu.Unlock()
+	for i, sc := range chunk.rows[rowOffset] {
+		if sc.stored {
+			fn(i+1, sc.Cell)
+		}
+	}
+}
+
+// AddPending Hold the update of the asynchronous operation with the sequence number until ApplyPending
+// RowFlushedError is returned once the row of the update has been released.
+func (s *cellStore) AddPending(seq uint64, u cellUpdate) error {
+	if err := s.checkReleased(u.rowIndex); err != nil {
+		return err
+	}
+	chunkIdx, _ := chunkIndex(u.rowIndex)
+	chunk := s.getChunk(chunkIdx, true)
+
+	chunk.mu.Lock()
+	defer chunk.mu.Unlock()
+	if err := s.checkReleased(u.rowIndex); err != nil {
+		return err
+	}
+	if len(chunk.pending) == 0 {
+		s.mu.Lock()
+		if s.pendingChunks == nil {
+			s.pendingChunks = make(map[int]struct{})
+		}
+		s.pendingChunks[chunkIdx] = struct{}{}
+		s.mu.Unlock()
+	}
+	chunk.pending = append(chunk.pending, pendingUpdate{seq: seq, cellUpdate: u})
+	return nil
+}
+
+// PendingChunks Get the chunks holding pending updates in row order
+func (s *cellStore) PendingChunks() []*cellChunk {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	chunks := make([]*cellChunk, 0, len(s.pendingChunks))
+	for chunkIdx := range s.pendingChunks {
+		chunks = append(chunks, s.chunks[chunkIdx])
+	}
+	sort.Slice(chunks, func(i, j int) bool {
+		return chunks[i].idx < chunks[j].idx
+	})
+	return chunks
+}
+
+// ApplyPending Call apply with the pending updates of the chunk of the operations up to lastSeq in the order of the operations
+// Updates of a single operation keep the order they were added in.
+// Unless wait is true, nothing is applied while another goroutine is applying the updates of the chunk.
+// The updates returned by apply are kept for later in front of the updates added meanwhile.
+func (s *cellStore) ApplyPending(chunk *cellChunk, lastSeq uint64, wait bool, apply func(chunkIdx int, updates []pendingUpdate) ([]pendingUpdate, error)) error {
+	if wait {
+		chunk.applyMu.Lock()
+	} else if !chunk.applyMu.TryLock() {
+		return nil
+	}
+	defer chunk.applyMu.Unlock()
+
+	chunk.mu.Lock()
+	updates := chunk.pending
+	chunk.pending = nil
+	for i, u := range updates {
+		if u.seq <= lastSeq {
+			continue
+		}
+		// some of the updates belong to operations after lastSeq, which are kept for later
+		taken := append([]pendingUpdate(nil), updates[:i]...)
+		for _, u := range updates[i:] {
+			if u.seq <= lastSeq {
+				taken = append(taken, u)
+			} else {
+				chunk.pending = append(chunk.pending, u)
+			}
+		}
+		updates = taken
+		break
+	}
+	chunk.mu.Unlock()
+
+	var (
+		rest []pendingUpdate
+		err  error
+	)
+	if len(updates) > 0 {
+		slices.SortStableFunc(updates, func(a, b pendingUpdate) int {
+			return cmp.Compare(a.seq, b.seq)
+		})
+		rest, err = apply(chunk.idx, updates)
+	}
+
+	// the chunk stays listed until its updates are applied, so that waiting callers wait for them through applyMu
+	chunk.mu.Lock()
+	defer chunk.mu.Unlock()
+	if len(rest) > 0 && err == nil {
+		chunk.pending = slices.Concat(rest, chunk.pending)
+	}
+	if len(chunk.pending) == 0 {
+		s.mu.Lock()
+		delete(s.pendingChunks, chunk.idx)
+		// a released chunk kept by DeleteRows for its pending updates is dropped once they are applied
+		if (chunk.idx+1)*cellChunkRows <= int(s.releasedRow.Load()) {
+			delete(s.chunks, chunk.idx)
+		}
+		s.mu.Unlock()
+	}
+	return err
+}
+
+// AddSpan Record that the operation with the sequence number has added updates to chunkIdx after prevChunkIdx
+// It must be called while the operation is running, before its updates can be applied.
+func (s *cellStore) AddSpan(seq uint64, prevChunkIdx, chunkIdx int) {
+	s.spanMu.Lock()
+	defer s.spanMu.Unlock()
+	span, ok := s.spans[seq]
+	if !ok {
+		if s.spans == nil {
+			s.spans = make(map[uint64]*pendingSpan)
+		}
+		span = &pendingSpan{chunks: []int{prevChunkIdx}, changed: make(chan struct{})}
+		s.spans[seq] = span
+		s.spanCount.Add(1)
+	}
+	span.chunks = append(span.chunks, chunkIdx)
+}
+
+// WaitSpan Wait until the updates of the operation with the sequence number in the chunk are the next ones to be applied
+// ok reports whether the operation spans chunks, in which case ready reports whether the updates are to be applied
+// and failed whether they are to be skipped, since the operation has failed in a previous chunk.
+// Unless wait is true, ready is false instead of waiting.
+func (s *cellStore) WaitSpan(ctx context.Context, seq uint64, chunkIdx int, wait bool) (ok, ready, failed bool, err error) {
+	if s.spanCount.Load() == 0 {
+		return false, false, false, nil
+	}
+	s.spanMu.Lock()
+	defer s.spanMu.Unlock()
+	span, ok := s.spans[seq]
+	if !ok {
+		return false, false, false, nil
+	}
+	for !span.failed && span.chunks[0] != chunkIdx {
+		if !wait {
+			return true, false, false, nil
+		}
+		changed := span.changed
+		s.spanMu.Unlock()
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		s.spanMu.Lock()
+		if err != nil {
+			return true, false, false, err
+		}
+	}
+	return true, true, span.failed, nil
+}
+
+// DoneSpan Mark the updates of the operation with the sequence number in the chunk as applied, failed reports whether one of them failed
+func (s *cellStore) DoneSpan(seq uint64, chunkIdx int, failed bool) {
+	s.spanMu.Lock()
+	defer s.spanMu.Unlock()
+	span, ok := s.spans[seq]
+	if !ok {
+		return
+	}
+	if i := slices.Index(span.chunks, chunkIdx); i >= 0 {
+		span.chunks = slices.Delete(span.chunks, i, i+1)
+	}
+	span.failed = span.failed || failed
+	close(span.changed)
+	span.changed = make(chan struct{})
+	if len(span.chunks) == 0 {
+		delete(s.spans, seq)
+		s.spanCount.Add(-1)
+	}
+}
+
+// DeleteRows Release the rows from startRowIndex to endRowIndex, which must have been passed to ReleaseRows
+// Rows before startRowIndex are expected to be released already, so a chunk is dropped as soon as its last row is released.
+// A chunk holding pending updates is kept until ApplyPending, so that their RowFlushedError is reported.
+func (s *cellStore) DeleteRows(startRowIndex, endRowIndex int) {
+	for rowIdx := startRowIndex; rowIdx <= endRowIndex; {
+		chunkIdx, rowOffset := chunkIndex(rowIdx)
+		chunkEndRowIdx := (chunkIdx + 1) * cellChunkRows
+		if chunkEndRowIdx <= endRowIndex {
+			s.deleteChunk(chunkIdx)
+			rowIdx = chunkEndRowIdx + 1
+			continue
+		}
+		if chunk := s.getChunk(chunkIdx, false); chunk != nil {
+			chunk.mu.Lock()
+			chunk.rows[rowOffset] = nil
+			chunk.mu.Unlock()
+		}
+		rowIdx++
+	}
+}
+
+// deleteChunk Drop the chunk unless it holds pending updates
+func (s *cellStore) deleteChunk(chunkIdx int) {
+	chunk := s.getChunk(chunkIdx, false)
+	if chunk == nil {
+		return
+	}
+	chunk.mu.Lock()
+	defer chunk.mu.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.pendingChunks[chunkIdx]; ok {
+		chunk.rows = [cellChunkRows][]storedCell{}
+		return
+	}
+	delete(s.chunks, chunkIdx)
+}
@@ -0,0 +1,159 @@
+package excelizeam
+
+import (
+	"github.com/xuri/excelize/v2"
+)
+
+// ConditionalCriteria Comparison of a cell value rule
+type ConditionalCriteria string
+
+const (
+	ConditionalCriteriaEqual              ConditionalCriteria = "=="
+	ConditionalCriteriaNotEqual           ConditionalCriteria = "!="
+	ConditionalCriteriaGreaterThan        ConditionalCriteria = ">"
+	ConditionalCriteriaGreaterThanOrEqual ConditionalCriteria = ">="
+	ConditionalCriteriaLessThan           ConditionalCriteria = "<"
+	ConditionalCriteriaLessThanOrEqual    ConditionalCriteria = "<="
+)
+
+// IconStyle Built-in icon set of an icon set rule
+type IconStyle string
+
+const (
+	IconStyle3Arrows        IconStyle = "3Arrows"
+	IconStyle3ArrowsGray    IconStyle = "3ArrowsGray"
+	IconStyle3Flags         IconStyle = "3Flags"
+	IconStyle3Symbols       IconStyle = "3Symbols"
+	IconStyle3TrafficLights IconStyle = "3TrafficLights1"
+	IconStyle4Arrows        IconStyle = "4Arrows"
+	IconStyle4Rating        IconStyle = "4Rating"
+	IconStyle5Arrows        IconStyle = "5Arrows"
+	IconStyle5Rating        IconStyle = "5Rating"
+)
+
+// ConditionalFormatRule Rule of a conditional format made by the *Rule functions
+type ConditionalFormatRule struct {
+	opts excelize.ConditionalFormatOptions
+	// style is registered as a differential format, which only holds the fields to be changed
+	style *excelize.Style
+}
+
+// CellRule Format the cells whose value matches value with criteria, such as below zero
+func CellRule(criteria ConditionalCriteria, value string, style excelize.Style) ConditionalFormatRule {
+	return ConditionalFormatRule{
+		opts:  excelize.ConditionalFormatOptions{Type: "cell", Criteria: string(criteria), Value: value},
+		style: &style,
+	}
+}
+
+// CellBetweenRule Format the cells whose value is between minValue and maxValue inclusive
+func CellBetweenRule(minValue, maxValue string, style excelize.Style) ConditionalFormatRule {
+	return ConditionalFormatRule{
+		opts:  excelize.ConditionalFormatOptions{Type: "cell", Criteria: "between", MinValue: minValue, MaxValue: maxValue},
+		style: &style,
+	}
+}
+
+// FormulaRule Format the cells for which formula is true
+// The formula is relative to the top left cell of the range, such as "$B1<0" for the range "A1:D10".
+func FormulaRule(formula string, style excelize.Style) ConditionalFormatRule {
+	return ConditionalFormatRule{
+		opts:  excelize.ConditionalFormatOptions{Type: "formula", Criteria: formula},
+		style: &style,
+	}
+}
+
+// ColorScaleRule Color the cells on a scale from minColor for the lowest value to maxColor for the highest value
+func ColorScaleRule(minColor, maxColor string) ConditionalFormatRule {
+	return ConditionalFormatRule{
+		opts: excelize.ConditionalFormatOptions{
+			Type:     "2_color_scale",
+			Criteria: "=",
+			MinType:  "min",
+			MaxType:  "max",
+			MinColor: minColor,
+			MaxColor: maxColor,
+		},
+	}
+}
+
+// ThreeColorScaleRule Color the cells on a scale from minColor through midColor at the median to maxColor
+func ThreeColorScaleRule(minColor, midColor, maxColor string) ConditionalFormatRule {
+	return ConditionalFormatRule{
+		opts: excelize.ConditionalFormatOptions{
+			Type:     "3_color_scale",
+			Criteria: "=",
+			MinType:  "min",
+			MidType:  "percentile",
+			MaxType:  "max",
+			MidValue: "50",
+			MinColor: minColor,
+			MidColor: midColor,
+			MaxColor: maxColor,
+		},
+	}
+}
+
+// DataBarRule Draw a bar of color in the cells proportional to their value
+func DataBarRule(color string) ConditionalFormatRule {
+	return ConditionalFormatRule{
+		opts: excelize.ConditionalFormatOptions{
+			Type:     "data_bar",
+			Criteria: "=",
+			MinType:  "min",
+			MaxType:  "max",
+			BarColor: color,
+		},
+	}
+}
+
+// IconSetRule Show an icon of iconStyle in the cells depending on their value
+func IconSetRule(iconStyle IconStyle) ConditionalFormatRule {
+	return ConditionalFormatRule{
+		opts: excelize.ConditionalFormatOptions{Type: "icon_set", IconStyle: string(iconStyle)},
+	}
+}
+
+// StopIfTrue Get the rule which stops the rules after it from being evaluated when it matches
+func (r ConditionalFormatRule) StopIfTrue() ConditionalFormatRule {
+	r.opts.StopIfTrue = true
+	return r
+}
+
+func (e *excelizeam) SetConditionalFormat(rangeRef string, rules []ConditionalFormatRule) error {
+	if err := e.checkFinished(); err != nil {
+		return err
+	}
+	opts := make([]excelize.ConditionalFormatOptions, len(rules))
+	for i, rule := range rules {
+		opts[i] = rule.opts
+		if rule.style == nil {
+			continue
+		}
+		formatID, err := e.wb.getConditionalStyleID(rule.style)
+		if err != nil {
+			return err
+		}
+		opts[i].Format = &formatID
+	}
+	e.wb.fileMu.Lock()
+	defer e.wb.fileMu.Unlock()
+	// the conditional formats are written by StreamWriter.Flush together with the rest of the worksheet
+	return e.wb.file.SetConditionalFormat(e.Name(), rangeRef, opts)
+}
+
+// getConditionalStyleID Get the ID of the differential format of the style, registering it when it is new
+func (wb *workbook) getConditionalStyleID(style *excelize.Style) (int, error) {
+	key, err := styleKey(style)
+	if err != nil {
+		return 0, err
+	}
+	return wb.dxfStore.LoadOrStore(key, func() (StoredStyle, error) {
+		styl := copyStyle(style)
+		formatID, err := wb.file.NewConditionalStyle(styl)
+		if err != nil {
+			return StoredStyle{}, err
+		}
+		return StoredStyle{StyleID: formatID, Style: styl}, nil
+	})
+}
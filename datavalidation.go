@@ -0,0 +1,186 @@
+package excelizeam
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// DataValidationListSheetName Hidden sheet holding the lists too long to be written in the data validation itself
+const DataValidationListSheetName = "excelizeam_lists"
+
+// DataValidationRule Rule of a data validation made by the *Rule functions
+type DataValidationRule struct {
+	set func(wb *workbook, dv *excelize.DataValidation) error
+
+	inputTitle string
+	inputMsg   string
+	errorStyle *excelize.DataValidationErrorStyle
+	errorTitle string
+	errorMsg   string
+}
+
+// ListRule Allow one of values chosen from a drop-down list
+// Lists longer than the limit of Excel, or with values containing commas or starting with "=", are written to DataValidationListSheetName.
+func ListRule(values ...string) DataValidationRule {
+	values = append([]string(nil), values...)
+	return DataValidationRule{
+		set: func(wb *workbook, dv *excelize.DataValidation) error {
+			inline := true
+			for _, value := range values {
+				// excelize.DataValidation.SetDropList takes a list starting with "=" as a formula
+				if strings.Contains(value, ",") || strings.HasPrefix(value, "=") {
+					inline = false
+					break
+				}
+			}
+			if inline {
+				err := dv.SetDropList(values)
+				if !errors.Is(err, excelize.ErrDataValidationFormulaLength) {
+					return err
+				}
+			}
+			ref, err := wb.addValidationList(values)
+			if err != nil {
+				return err
+			}
+			dv.SetSqrefDropList(ref)
+			return nil
+		},
+	}
+}
+
+// WholeNumberRule Allow whole numbers from minValue to maxValue
+func WholeNumberRule(minValue, maxValue int) DataValidationRule {
+	return rangeRule(minValue, maxValue, excelize.DataValidationTypeWhole)
+}
+
+// DecimalRule Allow numbers from minValue to maxValue
+func DecimalRule(minValue, maxValue float64) DataValidationRule {
+	return rangeRule(minValue, maxValue, excelize.DataValidationTypeDecimal)
+}
+
+// DateRule Allow dates from the date of from to the date of to
+func DateRule(from, to time.Time) DataValidationRule {
+	date := func(t time.Time) string {
+		return fmt.Sprintf("DATE(%d,%d,%d)", t.Year(), t.Month(), t.Day())
+	}
+	return rangeRule(date(from), date(to), excelize.DataValidationTypeDate)
+}
+
+// TextLengthRule Allow text whose length is from minLength to maxLength
+func TextLengthRule(minLength, maxLength int) DataValidationRule {
+	return rangeRule(minLength, maxLength, excelize.DataValidationTypeTextLength)
+}
+
+// CustomRule Allow values for which formula is true
+// The formula is relative to the top left cell of the range, such as "ISNUMBER(A1)" for the range "A1:A10".
+func CustomRule(formula string) DataValidationRule {
+	return DataValidationRule{
+		set: func(wb *workbook, dv *excelize.DataValidation) error {
+			if err := dv.SetRange(strings.TrimPrefix(formula, "="), "", excelize.DataValidationTypeCustom, excelize.DataValidationOperatorBetween); err != nil {
+				return err
+			}
+			// custom formulas have no operator
+			dv.Operator = ""
+			return nil
+		},
+	}
+}
+
+func rangeRule(minValue, maxValue interface{}, t excelize.DataValidationType) DataValidationRule {
+	return DataValidationRule{
+		set: func(wb *workbook, dv *excelize.DataValidation) error {
+			return dv.SetRange(minValue, maxValue, t, excelize.DataValidationOperatorBetween)
+		},
+	}
+}
+
+// WithInput Get the rule showing the input message when one of the cells is selected
+func (r DataValidationRule) WithInput(title, msg string) DataValidationRule {
+	r.inputTitle, r.inputMsg = title, msg
+	return r
+}
+
+// WithError Get the rule showing the error message of style when an invalid value is entered
+func (r DataValidationRule) WithError(style excelize.DataValidationErrorStyle, title, msg string) DataValidationRule {
+	r.errorStyle, r.errorTitle, r.errorMsg = &style, title, msg
+	return r
+}
+
+func (e *excelizeam) AddDataValidation(startColIndex, startRowIndex, endColIndex, endRowIndex int, rule DataValidationRule) error {
+	if err := e.checkFinished(); err != nil {
+		return err
+	}
+	if rule.set == nil {
+		return excelize.ErrParameterInvalid
+	}
+	startCell, err := excelize.CoordinatesToCellName(startColIndex, startRowIndex)
+	if err != nil {
+		return err
+	}
+	endCell, err := excelize.CoordinatesToCellName(endColIndex, endRowIndex)
+	if err != nil {
+		return err
+	}
+
+	dv := excelize.NewDataValidation(true)
+	if err := rule.set(e.wb, dv); err != nil {
+		return err
+	}
+	dv.SetSqref(startCell + ":" + endCell)
+	if rule.inputTitle != "" || rule.inputMsg != "" {
+		dv.SetInput(rule.inputTitle, rule.inputMsg)
+	}
+	if rule.errorStyle != nil {
+		dv.SetError(*rule.errorStyle, rule.errorTitle, rule.errorMsg)
+	}
+	e.wb.fileMu.Lock()
+	defer e.wb.fileMu.Unlock()
+	// the data validations are written by StreamWriter.Flush together with the rest of the worksheet
+	return e.wb.file.AddDataValidation(e.Name(), dv)
+}
+
+// addValidationList Write the values to a new column of DataValidationListSheetName and get the reference to them
+func (wb *workbook) addValidationList(values []string) (string, error) {
+	wb.fileMu.Lock()
+	defer wb.fileMu.Unlock()
+	idx, err := wb.file.GetSheetIndex(DataValidationListSheetName)
+	if err != nil {
+		return "", err
+	}
+	if idx == -1 {
+		if _, err := wb.file.NewSheet(DataValidationListSheetName); err != nil {
+			return "", err
+		}
+		if err := wb.file.SetSheetVisible(DataValidationListSheetName, false); err != nil {
+			return "", err
+		}
+		wb.validationListCols = 0
+	} else if wb.validationListCols == 0 {
+		// the sheet of an existing workbook keeps its lists
+		cols, err := wb.file.GetCols(DataValidationListSheetName)
+		if err != nil {
+			return "", err
+		}
+		wb.validationListCols = len(cols)
+	}
+
+	colIdx := wb.validationListCols + 1
+	startCell, err := excelize.CoordinatesToCellName(colIdx, 1, true)
+	if err != nil {
+		return "", err
+	}
+	endCell, err := excelize.CoordinatesToCellName(colIdx, max(len(values), 1), true)
+	if err != nil {
+		return "", err
+	}
+	if err := wb.file.SetSheetCol(DataValidationListSheetName, strings.ReplaceAll(startCell, "$", ""), &values); err != nil {
+		return "", err
+	}
+	wb.validationListCols = colIdx
+	return fmt.Sprintf("'%s'!%s:%s", DataValidationListSheetName, startCell, endCell), nil
+}
@@ -1,11 +1,12 @@
 package excelizeam
 
 import (
+	"context"
 	"crypto/sha1"
 	"errors"
 	"fmt"
 	"io"
-	"strconv"
+	"runtime"
 	"strings"
 	"sync"
 
@@ -18,14 +19,78 @@ import (
 var (
 	ErrOverrideCellValue = errors.New("override cell value")
 	ErrOverrideCellStyle = errors.New("override cell style")
+	ErrRowFlushed        = errors.New("row already flushed")
 )
 
+// CellError is returned when an operation on a cell fails
+// Err holds the cause such as ErrOverrideCellValue or ErrOverrideCellStyle.
+type CellError struct {
+	Op       string
+	Col      int
+	Row      int
+	CellName string
+	Err      error
+}
+
+func newCellError(op string, colIndex, rowIndex int, err error) *CellError {
+	cellName, _ := excelize.CoordinatesToCellName(colIndex, rowIndex)
+	return &CellError{
+		Op:       op,
+		Col:      colIndex,
+		Row:      rowIndex,
+		CellName: cellName,
+		Err:      err,
+	}
+}
+
+func (e *CellError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Op, e.CellName, e.Err)
+}
+
+func (e *CellError) Unwrap() error {
+	return e.Err
+}
+
+// RowFlushedError is returned when writing to a row that has already been flushed to the StreamWriter
+type RowFlushedError struct {
+	RowIndex        int
+	FlushedRowIndex int
+}
+
+func (e *RowFlushedError) Error() string {
+	return fmt.Sprintf("%s: row %d (flushed up to row %d)", ErrRowFlushed, e.RowIndex, e.FlushedRowIndex)
+}
+
+func (e *RowFlushedError) Unwrap() error {
+	return ErrRowFlushed
+}
+
 type Excelizeam interface {
+	Sheet
+
+	// Write StreamWriter
+	Write(w io.Writer) error
+
+	// File Get the original excelize.File
+	File() (*excelize.File, error)
+
+	// Close Release the temporary files held by the workbook, see Workbook.Close
+	Close() error
+}
+
+type Sheet interface {
+	// Name Get the sheet name
+	Name() string
+
 	// Preparations in advance
 
 	// SetDefaultBorderStyle Set default cell border
 	// For example, use when you want to paint the cell background white
+	// Each row gets the border up to the last column set to the sheet when the row is written,
+	// so rows flushed by FlushRows miss the columns set afterwards unless SetDefaultBorderColumns covers them.
 	SetDefaultBorderStyle(style excelizestyle.BorderStyle, color excelizestyle.BorderColor) error
+	// SetDefaultBorderColumns Set the number of columns the default border covers at least, 0 only covers the columns set to the sheet
+	SetDefaultBorderColumns(colCount int) error
 
 	// Excelize StreamWriter Wrapper
 
@@ -36,10 +101,41 @@ type Excelizeam interface {
 	SetColWidthRange(colIndexMin, colIndexMax int, width float64) error
 	MergeCell(startColIndex, startRowIndex, endColIndex, endRowIndex int) error
 
+	// SetFreezePanes Freeze the first cols columns and the first rows rows, 0 for both removes the panes
+	// Panes are applied to the StreamWriter before the first row, so they must be set before FlushRows writes any row to it.
+	SetFreezePanes(cols, rows int) error
+	// SetSplitPanes Split the view at xSplit and ySplit in twentieths of a point, 0 for both removes the panes
+	SetSplitPanes(xSplit, ySplit int) error
+	// SetPanes Set the panes as is, nil removes the panes
+	SetPanes(panes *excelize.Panes) error
+
 	// SetCellValue Set value and style to cell
 	SetCellValue(colIndex, rowIndex int, value interface{}, style *excelize.Style, overrideValue, overrideStyle bool) error
 	// SetCellValueAsync Set value and style to cell asynchronously
+	// The value of a cell which already has one is never overridden, see SetCellValueWithOverrideAsync.
 	SetCellValueAsync(colIndex, rowIndex int, value interface{}, style *excelize.Style, overrideStyle bool)
+	// SetCellValueWithOverrideAsync Set value and style to cell asynchronously with the override flags of SetCellValue
+	// Overrides are resolved in the order of the calls as with SetCellValue, so with overrideValue the last call wins.
+	SetCellValueWithOverrideAsync(colIndex, rowIndex int, value interface{}, style *excelize.Style, overrideValue, overrideStyle bool)
+
+	// SetCellFormula Set formula and style to cell, resolving them with the default policy of the sheet when the cell already has them
+	// The ref of an array formula must start at the cell, otherwise it fails with ErrArrayFormulaRef.
+	SetCellFormula(colIndex, rowIndex int, formula Formula, style *excelize.Style) error
+	// SetCellFormulaAsync Set formula and style to cell asynchronously, resolving them with the default policy of the sheet when the cell already has them
+	SetCellFormulaAsync(colIndex, rowIndex int, formula Formula, style *excelize.Style)
+
+	// SetCellRichText Set rich text and style to cell, resolving them with the default policy of the sheet when the cell already has them
+	// The font of each run takes precedence over the font of the style.
+	SetCellRichText(colIndex, rowIndex int, runs []excelize.RichTextRun, style *excelize.Style) error
+	// SetCellRichTextAsync Set rich text and style to cell asynchronously, resolving them with the default policy of the sheet when the cell already has them
+	SetCellRichTextAsync(colIndex, rowIndex int, runs []excelize.RichTextRun, style *excelize.Style)
+
+	// SetCellHyperlink Set hyperlink to cell, link is a URL or a location such as "Sheet1!A1" depending on linkType
+	// The cell gets display as its value and the hyperlink style set by WithHyperlinkStyle laid over its style.
+	// The value is resolved with the default policy of the sheet when the cell already has one.
+	SetCellHyperlink(colIndex, rowIndex int, link string, linkType HyperlinkType, display, tooltip string) error
+	// SetCellHyperlinkAsync Set hyperlink to cell asynchronously, see SetCellHyperlink
+	SetCellHyperlinkAsync(colIndex, rowIndex int, link string, linkType HyperlinkType, display, tooltip string)
 
 	// SetStyleCell Set style to cell
 	SetStyleCell(colIndex, rowIndex int, style excelize.Style, override bool) error
@@ -56,33 +152,147 @@ type Excelizeam interface {
 	// SetBorderRangeAsync Set border around cell range asynchronously
 	SetBorderRangeAsync(startColIndex, startRowIndex, endColIndex, endRowIndex int, borderRange BorderRange, override bool)
 
+	// SetRowHeight Set the height of the row in points
+	SetRowHeight(rowIndex int, height float64) error
+	// SetRowHeightAsync Set the height of the row in points asynchronously
+	SetRowHeightAsync(rowIndex int, height float64)
+
+	// SetRowHidden Hide or show the row
+	SetRowHidden(rowIndex int, hidden bool) error
+	// SetRowHiddenAsync Hide or show the row asynchronously
+	SetRowHiddenAsync(rowIndex int, hidden bool)
+
+	// SetRowOutlineLevel Set the outline level of the row from 0 to 7 to group rows
+	SetRowOutlineLevel(rowIndex int, level int) error
+	// SetRowOutlineLevelAsync Set the outline level of the row from 0 to 7 to group rows asynchronously
+	SetRowOutlineLevelAsync(rowIndex int, level int)
+
+	// SetRowStyle Set the style of the row, which applies to the cells of the row without value or style
+	SetRowStyle(rowIndex int, style excelize.Style) error
+	// SetRowStyleAsync Set the style of the row asynchronously, which applies to the cells of the row without value or style
+	SetRowStyleAsync(rowIndex int, style excelize.Style)
+
+	// SetOverridePolicy Set the default policy of the *WithPolicy methods called with the zero OverridePolicy
+	// Default is OverridePolicyError.
+	// The methods taking override flags use OverridePolicyMerge for true and OverridePolicyError for false.
+	SetOverridePolicy(policy OverridePolicy)
+
+	// SetCellValueWithPolicy Set value and style to cell, resolving both with policy when the cell already has them
+	SetCellValueWithPolicy(colIndex, rowIndex int, value interface{}, style *excelize.Style, policy OverridePolicy) error
+	// SetCellValueWithPolicyAsync Set value and style to cell asynchronously, resolving both with policy when the cell already has them
+	SetCellValueWithPolicyAsync(colIndex, rowIndex int, value interface{}, style *excelize.Style, policy OverridePolicy)
+
+	// SetStyleCellWithPolicy Set style to cell, resolving it with policy when the cell already has a style
+	SetStyleCellWithPolicy(colIndex, rowIndex int, style excelize.Style, policy OverridePolicy) error
+	// SetStyleCellWithPolicyAsync Set style to cell asynchronously, resolving it with policy when the cell already has a style
+	SetStyleCellWithPolicyAsync(colIndex, rowIndex int, style excelize.Style, policy OverridePolicy)
+
+	// SetStyleCellRangeWithPolicy Set style to cell with range, resolving it with policy for each cell which already has a style
+	SetStyleCellRangeWithPolicy(startColIndex, startRowIndex, endColIndex, endRowIndex int, style excelize.Style, policy OverridePolicy) error
+	// SetStyleCellRangeWithPolicyAsync Set style to cell with range asynchronously, resolving it with policy for each cell which already has a style
+	SetStyleCellRangeWithPolicyAsync(startColIndex, startRowIndex, endColIndex, endRowIndex int, style excelize.Style, policy OverridePolicy)
+
+	// SetBorderRangeWithPolicy Set border around cell range, resolving it with policy for each cell which already has a style
+	SetBorderRangeWithPolicy(startColIndex, startRowIndex, endColIndex, endRowIndex int, borderRange BorderRange, policy OverridePolicy) error
+	// SetBorderRangeWithPolicyAsync Set border around cell range asynchronously, resolving it with policy for each cell which already has a style
+	SetBorderRangeWithPolicyAsync(startColIndex, startRowIndex, endColIndex, endRowIndex int, borderRange BorderRange, policy OverridePolicy)
+
+	// SetAsyncLimit Set the number of workers running asynchronous operations
+	// and the number of operations handed to a worker at once.
+	// Async methods block while all workers are busy.
+	SetAsyncLimit(workers, batchSize int) error
+
+	// SetAsyncErrorLimit Set the number of asynchronous operation errors returned by Wait
+	// Every error up to limit is collected and returned joined by errors.Join, 0 collects all of them.
+	// Default is DefaultAsyncErrorLimit, which returns only the first error.
+	SetAsyncErrorLimit(limit int) error
+
+	// StyleByID Get the style registered with the style ID
+	// The style is a copy, so changing it leaves the registered style as is.
+	StyleByID(styleID int) (*excelize.Style, bool)
+
+	// StyleIDFromCell Get the style ID of the cell in a sheet of the workbook, such as a cell of a template
+	// Setting the style got by StyleByID with the style ID reuses the style ID.
+	StyleIDFromCell(sheet, cell string) (int, error)
+
+	// AddTable Add a table with header filters over the cell range, whose first row is the header
+	// The range must have a data row below the header, and the header cells must already hold unique strings.
+	// The range of opts is overridden, and an empty style name uses DefaultTableStyleName.
+	// The table is registered when the sheet is written, and a sheet can hold only one table.
+	AddTable(startColIndex, startRowIndex, endColIndex, endRowIndex int, opts *excelize.Table) error
+
+	// SetConditionalFormat Set the conditional format made of rules to the cell range such as "A1:D10"
+	// The rules are made by CellRule, CellBetweenRule, FormulaRule, ColorScaleRule, ThreeColorScaleRule, DataBarRule and IconSetRule,
+	// and are evaluated in the order given.
+	SetConditionalFormat(rangeRef string, rules []ConditionalFormatRule) error
+
+	// AddDataValidation Add the data validation of rule to the cell range
+	// The rules are made by ListRule, WholeNumberRule, DecimalRule, DateRule, TextLengthRule and CustomRule,
+	// and can show messages with WithInput and WithError.
+	AddDataValidation(startColIndex, startRowIndex, endColIndex, endRowIndex int, rule DataValidationRule) error
+
+	// Import Load the cells with their formulas, style IDs, merged cells, row options and column widths of the sheet of f
+	// Imported cells replace the cells already set at the same coordinates,
+	// and later changes to them follow the override rules as with any other cell.
+	// Cells with style only are imported as well, while the cells of a row with style which only inherit it are left to the row.
+	// Import must be called before any row is flushed.
+	Import(f *excelize.File, sheet string) error
+
+	// FlushRows Write all rows up to rowIndex to the StreamWriter and release them from memory
+	// Writing to a flushed row afterwards returns RowFlushedError,
+	// also for asynchronous operations called concurrently with FlushRows whose changes are applied after it.
+	// The default border of the flushed rows ends at the last column set so far, see SetDefaultBorderStyle.
+	FlushRows(rowIndex int) error
+
 	// Wait
 	// Wait for all running asynchronous operations to finish
+	// The changes of asynchronous operations are applied to the cells in the order the methods were called.
 	Wait() error
 
-	// Write StreamWriter
-	Write(w io.Writer) error
-
-	// File Get the original excelize.File
-	File() (*excelize.File, error)
+	// SetCSVFormulaMode Set what CSVRecords exports for formula cells
+	// Default is CSVFormulaValue.
+	SetCSVFormulaMode(mode CSVFormulaMode)
 
 	// CSVRecords Make csv records
+	// Rich text is flattened into plain text.
 	CSVRecords() ([][]string, error)
 }
 
 type excelizeam struct {
-	sw   *excelize.StreamWriter
-	file *excelize.File
+	wb *workbook
+	sw *excelize.StreamWriter
 
-	eg errgroup.Group
+	async *asyncQueue
 
+	// mu guards maxRow, maxCol, flushedRow, rowWritten, finishedErr, rowOptions, table, panes, arrayFormulas, defaultBorder, defaultBorderCols, defaultPolicy and csvFormulaMode
+	// Cells are guarded by cellStore, which applies every change to a cell atomically.
 	mu     sync.Mutex
 	maxRow int
 	maxCol int
 
+	// flushedRow rows up to this index have already been written to the StreamWriter
+	flushedRow int
+	// rowWritten a row has been written to the StreamWriter, which takes no panes from then on
+	rowWritten bool
+	// finishedErr error returned by the methods changing the sheet once it has been written or closed
+	finishedErr error
+	// rowOptions options of the rows not flushed yet keyed by row index
+	rowOptions map[int]*rowOptions
+	// table added to the StreamWriter after all rows have been written
+	table *excelize.Table
+	// panes set to the StreamWriter before the first row, nil once they have been set
+	panes *excelize.Panes
+	// arrayFormulas refs of the array formulas written to the StreamWriter keyed by cell name
+	arrayFormulas map[string]string
+
 	defaultBorder *DefaultBorders
-	styleStore    sync.Map
-	cellStore     sync.Map
+	// defaultBorderCols number of columns the default border covers at least
+	defaultBorderCols int
+	// defaultPolicy policy used by the *WithPolicy methods called with the zero OverridePolicy
+	defaultPolicy OverridePolicy
+	// csvFormulaMode what CSVRecords exports for formula cells
+	csvFormulaMode CSVFormulaMode
+	cellStore      cellStore
 }
 
 type DefaultBorders struct {
@@ -107,27 +317,53 @@ type BorderRange struct {
 	Inside *BorderItem
 }
 
-type StoredStyle struct {
-	StyleID int
-	Style   *excelize.Style
-}
-
 type Cell struct {
 	StyleID int
 	Value   interface{}
 }
 
-func New(sheetName string) (Excelizeam, error) {
-	f := excelize.NewFile()
-	err := f.SetSheetName("Sheet1", sheetName)
+func New(sheetName string, opts ...Option) (Excelizeam, error) {
+	wb, err := newWorkbook(opts)
+	if err != nil {
+		return nil, err
+	}
+	return wb.addSheet(sheetName)
+}
+
+// NewWithContext Create Excelizeam bound to ctx
+// Once ctx is done, queued asynchronous operations are skipped and Write returns ctx.Err().
+func NewWithContext(ctx context.Context, sheetName string, opts ...Option) (Excelizeam, error) {
+	return New(sheetName, append(opts, WithContext(ctx))...)
+}
+
+// NewFromFile Create Excelizeam streaming into the sheet of the existing workbook at path, such as a template
+// The sheet is created when it does not exist. The other sheets and the styles of the workbook are left intact,
+// and the cells, merged cells, row options and column widths already in the sheet are kept, see Sheet.Import.
+func NewFromFile(path, sheetName string, opts ...Option) (Excelizeam, error) {
+	wb, err := openWorkbook(func(o ...excelize.Options) (*excelize.File, error) {
+		return excelize.OpenFile(path, o...)
+	}, opts)
 	if err != nil {
 		return nil, err
 	}
-	sw, err := f.NewStreamWriter(sheetName)
+	return wb.addSheet(sheetName)
+}
+
+// NewFromReader Create Excelizeam streaming into the sheet of the existing workbook read from r, such as a template
+// The sheet is created when it does not exist. The other sheets and the styles of the workbook are left intact,
+// and the cells, merged cells, row options and column widths already in the sheet are kept, see Sheet.Import.
+func NewFromReader(r io.Reader, sheetName string, opts ...Option) (Excelizeam, error) {
+	wb, err := openWorkbook(func(o ...excelize.Options) (*excelize.File, error) {
+		return excelize.OpenReader(r, o...)
+	}, opts)
 	if err != nil {
 		return nil, err
 	}
-	return &excelizeam{sw: sw, file: f}, nil
+	return wb.addSheet(sheetName)
+}
+
+func (e *excelizeam) Name() string {
+	return e.sw.Sheet
 }
 
 func (e *excelizeam) SetDefaultBorderStyle(style excelizestyle.BorderStyle, color excelizestyle.BorderColor) error {
@@ -137,7 +373,7 @@ func (e *excelizeam) SetDefaultBorderStyle(style excelizestyle.BorderStyle, colo
 		Left:   excelizestyle.Border(excelizestyle.BorderPositionLeft, style, color),
 		Right:  excelizestyle.Border(excelizestyle.BorderPositionRight, style, color),
 	}
-	styleID, err := e.getStyleID(&excelize.Style{
+	styleID, err := e.wb.getStyleID(&excelize.Style{
 		Border: []excelize.Border{
 			db.Top,
 			db.Bottom,
@@ -149,34 +385,67 @@ func (e *excelizeam) SetDefaultBorderStyle(style excelizestyle.BorderStyle, colo
 		return err
 	}
 	db.StyleID = styleID
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.finishedErr != nil {
+		return e.finishedErr
+	}
 	e.defaultBorder = db
 	return nil
 }
 
+func (e *excelizeam) SetDefaultBorderColumns(colCount int) error {
+	if colCount < 0 || colCount > excelize.MaxColumns {
+		return excelize.ErrColumnNumber
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.finishedErr != nil {
+		return e.finishedErr
+	}
+	e.defaultBorderCols = colCount
+	return nil
+}
+
 func (e *excelizeam) SetColWidth(colIndex int, width float64) error {
+	if err := e.checkFinished(); err != nil {
+		return err
+	}
 	return e.sw.SetColWidth(colIndex, colIndex, width)
 }
 
 func (e *excelizeam) SetColWidthRange(colIndexMin, colIndexMax int, width float64) error {
+	if err := e.checkFinished(); err != nil {
+		return err
+	}
 	return e.sw.SetColWidth(colIndexMin, colIndexMax, width)
 }
 
 func (e *excelizeam) SetPageMargins(options *excelize.PageLayoutMarginsOptions) error {
-	return e.file.SetPageMargins(
+	if err := e.checkFinished(); err != nil {
+		return err
+	}
+	return e.wb.file.SetPageMargins(
 		e.sw.Sheet,
 		options,
 	)
 }
 
 func (e *excelizeam) SetPageLayout(options *excelize.PageLayoutOptions) error {
-	return e.file.SetPageLayout(e.sw.Sheet, options)
+	if err := e.checkFinished(); err != nil {
+		return err
+	}
+	return e.wb.file.SetPageLayout(e.sw.Sheet, options)
 }
 
 func (e *excelizeam) GetPageLayout() (excelize.PageLayoutOptions, error) {
-	return e.file.GetPageLayout(e.sw.Sheet)
+	return e.wb.file.GetPageLayout(e.sw.Sheet)
 }
 
 func (e *excelizeam) MergeCell(startColIndex, startRowIndex, endColIndex, endRowIndex int) error {
+	if err := e.checkFinished(); err != nil {
+		return err
+	}
 	startCell, err := excelize.CoordinatesToCellName(startColIndex, startRowIndex)
 	if err != nil {
 		return err
@@ -189,160 +458,188 @@ func (e *excelizeam) MergeCell(startColIndex, startRowIndex, endColIndex, endRow
 }
 
 func (e *excelizeam) SetCellValueAsync(colIndex, rowIndex int, value interface{}, style *excelize.Style, overrideStyle bool) {
-	e.eg.Go(func() error {
-		return e.setCellValue(colIndex, rowIndex, value, style, false, overrideStyle)
+	e.async.Go(func(seq uint64) error {
+		return e.setCellValue("SetCellValueAsync", colIndex, rowIndex, value, style, overridePolicy(false), overridePolicy(overrideStyle), e.addPendingUpdate(seq))
+	})
+}
+
+func (e *excelizeam) SetCellValueWithOverrideAsync(colIndex, rowIndex int, value interface{}, style *excelize.Style, overrideValue bool, overrideStyle bool) {
+	e.async.Go(func(seq uint64) error {
+		return e.setCellValue("SetCellValueWithOverrideAsync", colIndex, rowIndex, value, style, overridePolicy(overrideValue), overridePolicy(overrideStyle), e.addPendingUpdate(seq))
 	})
 }
 
 func (e *excelizeam) SetCellValue(colIndex, rowIndex int, value interface{}, style *excelize.Style, overrideValue bool, overrideStyle bool) error {
-	if err := e.eg.Wait(); err != nil {
+	if err := e.async.Wait(); err != nil {
 		return err
 	}
-	return e.setCellValue(colIndex, rowIndex, value, style, overrideValue, overrideStyle)
+	return e.setCellValue("SetCellValue", colIndex, rowIndex, value, style, overridePolicy(overrideValue), overridePolicy(overrideStyle), e.applyUpdate)
 }
 
-func (e *excelizeam) setCellValue(colIndex, rowIndex int, value interface{}, style *excelize.Style, overrideValue bool, overrideStyle bool) error {
-	e.checkMaxIndex(colIndex, rowIndex)
-	key := e.getCacheKey(colIndex, rowIndex)
+func (e *excelizeam) SetCellValueWithPolicyAsync(colIndex, rowIndex int, value interface{}, style *excelize.Style, policy OverridePolicy) {
+	policy = e.overridePolicy(policy)
+	e.async.Go(func(seq uint64) error {
+		return e.setCellValue("SetCellValueWithPolicyAsync", colIndex, rowIndex, value, style, policy, policy, e.addPendingUpdate(seq))
+	})
+}
 
-	styleID, err := e.getStyleID(style)
-	if err != nil {
+func (e *excelizeam) SetCellValueWithPolicy(colIndex, rowIndex int, value interface{}, style *excelize.Style, policy OverridePolicy) error {
+	if err := e.async.Wait(); err != nil {
 		return err
 	}
-	if cached, ok := e.cellStore.LoadOrStore(key, &Cell{
-		StyleID: styleID,
-		Value:   value,
-	}); ok {
-		cell := cached.(*Cell)
-		if cell.Value != nil && value != nil && !overrideValue {
-			return ErrOverrideCellValue
-		}
-		if value != nil {
-			cell.Value = value
-		}
+	policy = e.overridePolicy(policy)
+	return e.setCellValue("SetCellValueWithPolicy", colIndex, rowIndex, value, style, policy, policy, e.applyUpdate)
+}
 
-		if style != nil {
-			if cell.StyleID > 0 {
-				if !overrideStyle {
-					return ErrOverrideCellStyle
-				}
-				styleID, err = e.overrideStyle(cell.StyleID, *style)
-				if err != nil {
-					return err
-				}
-			}
-			cell.StyleID = styleID
+func (e *excelizeam) setCellValue(op string, colIndex, rowIndex int, value interface{}, style *excelize.Style, valuePolicy, stylePolicy OverridePolicy, apply applyUpdateFunc) error {
+	if f, ok := value.(Formula); ok && f.Array {
+		ref, err := arrayFormulaRef(colIndex, rowIndex, f.Ref)
+		if err != nil {
+			return newCellError(op, colIndex, rowIndex, err)
 		}
+		f.Ref = ref
+		value = f
 	}
-	return nil
+	if err := e.checkMaxIndex(rowIndex, colIndex, rowIndex); err != nil {
+		return newCellError(op, colIndex, rowIndex, err)
+	}
+	u := cellUpdate{
+		op:          op,
+		colIndex:    colIndex,
+		rowIndex:    rowIndex,
+		value:       value,
+		valuePolicy: valuePolicy,
+		stylePolicy: stylePolicy,
+	}
+	if err := u.setStyle(style); err != nil {
+		return newCellError(op, colIndex, rowIndex, err)
+	}
+	return apply(u)
 }
 
 func (e *excelizeam) SetStyleCellAsync(colIndex, rowIndex int, style excelize.Style, override bool) {
-	e.eg.Go(func() error {
-		err := e.setStyleCell(colIndex, rowIndex, style, override)
-		return err
+	e.async.Go(func(seq uint64) error {
+		return e.setStyleCell("SetStyleCellAsync", colIndex, rowIndex, style, overridePolicy(override), e.addPendingUpdate(seq))
 	})
 }
 
 func (e *excelizeam) SetStyleCell(colIndex, rowIndex int, style excelize.Style, override bool) error {
-	if err := e.eg.Wait(); err != nil {
+	if err := e.async.Wait(); err != nil {
 		return err
 	}
-	return e.setStyleCell(colIndex, rowIndex, style, override)
+	return e.setStyleCell("SetStyleCell", colIndex, rowIndex, style, overridePolicy(override), e.applyUpdate)
 }
 
-func (e *excelizeam) setStyleCell(colIndex, rowIndex int, style excelize.Style, override bool) error {
-	e.checkMaxIndex(colIndex, rowIndex)
-	key := e.getCacheKey(colIndex, rowIndex)
+func (e *excelizeam) SetStyleCellWithPolicyAsync(colIndex, rowIndex int, style excelize.Style, policy OverridePolicy) {
+	policy = e.overridePolicy(policy)
+	e.async.Go(func(seq uint64) error {
+		return e.setStyleCell("SetStyleCellWithPolicyAsync", colIndex, rowIndex, style, policy, e.addPendingUpdate(seq))
+	})
+}
 
-	styleID, err := e.getStyleID(&style)
-	if err != nil {
+func (e *excelizeam) SetStyleCellWithPolicy(colIndex, rowIndex int, style excelize.Style, policy OverridePolicy) error {
+	if err := e.async.Wait(); err != nil {
 		return err
 	}
-	if cached, ok := e.cellStore.LoadOrStore(key, &Cell{
-		StyleID: styleID,
-		Value:   nil,
-	}); ok {
-		c := cached.(*Cell)
-		if c.StyleID > 0 {
-			if !override {
-				return ErrOverrideCellStyle
-			}
-			styleID, err = e.overrideStyle(c.StyleID, style)
-			if err != nil {
-				return err
-			}
-			c.StyleID = styleID
-		}
-		return nil
+	return e.setStyleCell("SetStyleCellWithPolicy", colIndex, rowIndex, style, e.overridePolicy(policy), e.applyUpdate)
+}
+
+func (e *excelizeam) setStyleCell(op string, colIndex, rowIndex int, style excelize.Style, policy OverridePolicy, apply applyUpdateFunc) error {
+	if err := e.checkMaxIndex(rowIndex, colIndex, rowIndex); err != nil {
+		return newCellError(op, colIndex, rowIndex, err)
 	}
-	return nil
+	u := cellUpdate{op: op, colIndex: colIndex, rowIndex: rowIndex, stylePolicy: policy}
+	if err := u.setStyle(&style); err != nil {
+		return newCellError(op, colIndex, rowIndex, err)
+	}
+	return apply(u)
 }
 
 func (e *excelizeam) SetStyleCellRangeAsync(startColIndex, startRowIndex, endColIndex, endRowIndex int, style excelize.Style, override bool) {
-	e.eg.Go(func() error {
-		err := e.setStyleCellRange(startColIndex, startRowIndex, endColIndex, endRowIndex, style, override)
-		return err
+	e.async.Go(func(seq uint64) error {
+		return e.setStyleCellRange("SetStyleCellRangeAsync", startColIndex, startRowIndex, endColIndex, endRowIndex, style, overridePolicy(override), e.addPendingUpdate(seq))
 	})
 }
 
 func (e *excelizeam) SetStyleCellRange(startColIndex, startRowIndex, endColIndex, endRowIndex int, style excelize.Style, override bool) error {
-	if err := e.eg.Wait(); err != nil {
+	if err := e.async.Wait(); err != nil {
+		return err
+	}
+	return e.setStyleCellRange("SetStyleCellRange", startColIndex, startRowIndex, endColIndex, endRowIndex, style, overridePolicy(override), e.applyUpdate)
+}
+
+func (e *excelizeam) SetStyleCellRangeWithPolicyAsync(startColIndex, startRowIndex, endColIndex, endRowIndex int, style excelize.Style, policy OverridePolicy) {
+	policy = e.overridePolicy(policy)
+	e.async.Go(func(seq uint64) error {
+		return e.setStyleCellRange("SetStyleCellRangeWithPolicyAsync", startColIndex, startRowIndex, endColIndex, endRowIndex, style, policy, e.addPendingUpdate(seq))
+	})
+}
+
+func (e *excelizeam) SetStyleCellRangeWithPolicy(startColIndex, startRowIndex, endColIndex, endRowIndex int, style excelize.Style, policy OverridePolicy) error {
+	if err := e.async.Wait(); err != nil {
 		return err
 	}
-	return e.setStyleCellRange(startColIndex, startRowIndex, endColIndex, endRowIndex, style, override)
+	return e.setStyleCellRange("SetStyleCellRangeWithPolicy", startColIndex, startRowIndex, endColIndex, endRowIndex, style, e.overridePolicy(policy), e.applyUpdate)
 }
 
-func (e *excelizeam) setStyleCellRange(startColIndex, startRowIndex, endColIndex, endRowIndex int, style excelize.Style, override bool) error {
-	e.checkMaxIndex(endColIndex, endRowIndex)
+func (e *excelizeam) setStyleCellRange(op string, startColIndex, startRowIndex, endColIndex, endRowIndex int, style excelize.Style, policy OverridePolicy, apply applyUpdateFunc) error {
+	if err := e.checkMaxIndex(startRowIndex, endColIndex, endRowIndex); err != nil {
+		return newCellError(op, startColIndex, startRowIndex, err)
+	}
+	u := cellUpdate{op: op, stylePolicy: policy}
+	if err := u.setStyle(&style); err != nil {
+		return newCellError(op, startColIndex, startRowIndex, err)
+	}
 	for rowIdx := startRowIndex; rowIdx <= endRowIndex; rowIdx++ {
+		if err := e.wb.ctx.Err(); err != nil {
+			return err
+		}
 		for colIdx := startColIndex; colIdx <= endColIndex; colIdx++ {
-			key := e.getCacheKey(colIdx, rowIdx)
-
-			styleID, err := e.getStyleID(&style)
-			if err != nil {
+			u.colIndex, u.rowIndex = colIdx, rowIdx
+			if err := apply(u); err != nil {
 				return err
 			}
-			if cached, ok := e.cellStore.LoadOrStore(key, &Cell{
-				StyleID: styleID,
-				Value:   nil,
-			}); ok {
-				c := cached.(*Cell)
-				if c.StyleID > 0 {
-					if !override {
-						return ErrOverrideCellStyle
-					}
-					styleID, err = e.overrideStyle(c.StyleID, style)
-					if err != nil {
-						return err
-					}
-				}
-				c.StyleID = styleID
-			}
 		}
 	}
 	return nil
 }
 
 func (e *excelizeam) SetBorderRangeAsync(startColIndex, startRowIndex, endColIndex, endRowIndex int, borderRange BorderRange, override bool) {
-	e.eg.Go(func() error {
-		err := e.setBorderRange(startColIndex, startRowIndex, endColIndex, endRowIndex, borderRange, override)
-		return err
+	e.async.Go(func(seq uint64) error {
+		return e.setBorderRange("SetBorderRangeAsync", startColIndex, startRowIndex, endColIndex, endRowIndex, borderRange, overridePolicy(override), e.addPendingUpdate(seq))
 	})
 }
 
 func (e *excelizeam) SetBorderRange(startColIndex, startRowIndex, endColIndex, endRowIndex int, borderRange BorderRange, override bool) error {
-	if err := e.eg.Wait(); err != nil {
+	if err := e.async.Wait(); err != nil {
+		return err
+	}
+	return e.setBorderRange("SetBorderRange", startColIndex, startRowIndex, endColIndex, endRowIndex, borderRange, overridePolicy(override), e.applyUpdate)
+}
+
+func (e *excelizeam) SetBorderRangeWithPolicyAsync(startColIndex, startRowIndex, endColIndex, endRowIndex int, borderRange BorderRange, policy OverridePolicy) {
+	policy = e.overridePolicy(policy)
+	e.async.Go(func(seq uint64) error {
+		return e.setBorderRange("SetBorderRangeWithPolicyAsync", startColIndex, startRowIndex, endColIndex, endRowIndex, borderRange, policy, e.addPendingUpdate(seq))
+	})
+}
+
+func (e *excelizeam) SetBorderRangeWithPolicy(startColIndex, startRowIndex, endColIndex, endRowIndex int, borderRange BorderRange, policy OverridePolicy) error {
+	if err := e.async.Wait(); err != nil {
 		return err
 	}
-	return e.setBorderRange(startColIndex, startRowIndex, endColIndex, endRowIndex, borderRange, override)
+	return e.setBorderRange("SetBorderRangeWithPolicy", startColIndex, startRowIndex, endColIndex, endRowIndex, borderRange, e.overridePolicy(policy), e.applyUpdate)
 }
 
-func (e *excelizeam) setBorderRange(startColIndex, startRowIndex, endColIndex, endRowIndex int, borderRange BorderRange, override bool) error {
-	e.checkMaxIndex(endColIndex, endRowIndex)
+func (e *excelizeam) setBorderRange(op string, startColIndex, startRowIndex, endColIndex, endRowIndex int, borderRange BorderRange, policy OverridePolicy, apply applyUpdateFunc) error {
+	if err := e.checkMaxIndex(startRowIndex, endColIndex, endRowIndex); err != nil {
+		return newCellError(op, startColIndex, startRowIndex, err)
+	}
 	for rowIdx := startRowIndex; rowIdx <= endRowIndex; rowIdx++ {
+		if err := e.wb.ctx.Err(); err != nil {
+			return err
+		}
 		for colIdx := startColIndex; colIdx <= endColIndex; colIdx++ {
-			key := e.getCacheKey(colIdx, rowIdx)
 			borderStyles := make([]excelize.Border, 0, 4)
 			switch {
 			case rowIdx == startRowIndex && colIdx == startColIndex: // TopLeft
@@ -500,73 +797,235 @@ func (e *excelizeam) setBorderRange(startColIndex, startRowIndex, endColIndex, e
 			if len(borderStyles) == 0 {
 				continue
 			}
-			style := excelize.Style{Border: borderStyles}
-
-			styleID, err := e.getStyleID(&style)
-			if err != nil {
-				return err
+			u := cellUpdate{op: op, colIndex: colIdx, rowIndex: rowIdx, stylePolicy: policy}
+			if err := u.setStyle(&excelize.Style{Border: borderStyles}); err != nil {
+				return newCellError(op, colIdx, rowIdx, err)
 			}
-			if cached, ok := e.cellStore.LoadOrStore(key, &Cell{
-				StyleID: styleID,
-				Value:   nil,
-			}); ok {
-				c := cached.(*Cell)
-				if c.StyleID > 0 {
-					if !override {
-						return ErrOverrideCellStyle
-					}
-					styleID, err = e.overrideStyle(c.StyleID, style)
-					if err != nil {
-						return err
-					}
-				}
-				c.StyleID = styleID
-				continue
+			if err := apply(u); err != nil {
+				return err
 			}
 		}
 	}
 	return nil
 }
 
-func (e *excelizeam) getStyleID(style *excelize.Style) (int, error) {
-	var styl excelize.Style
+// cellUpdate A change to a single cell made by an operation
+type cellUpdate struct {
+	op       string
+	colIndex int
+	rowIndex int
+
+	// value is left as is when nil
+	value       interface{}
+	valuePolicy OverridePolicy
+
+	// style is left as is when nil
+	style       *excelize.Style
+	styleKey    [sha1.Size]byte
+	stylePolicy OverridePolicy
+
+	// hyperlink is left as is when nil
+	hyperlink *hyperlink
+}
+
+// applyUpdateFunc Apply the update to the cell now or later
+type applyUpdateFunc func(u cellUpdate) error
+
+// setStyle Set the style to be stored together with its canonical key
+func (u *cellUpdate) setStyle(style *excelize.Style) error {
 	if style == nil {
-		return 0, nil
+		return nil
 	}
-	if style != nil {
-		styl = *style
+	// copied, since the update may be applied after the caller has changed the style
+	styl := copyStyle(style)
+	key, err := styleKey(styl)
+	if err != nil {
+		return err
 	}
-	hash := fmt.Sprintf("%x", sha1.Sum([]byte(fmt.Sprintf("%+v", styl))))
+	u.style = styl
+	u.styleKey = key
+	return nil
+}
+
+// applyUpdate Apply the update to the cell
+func (e *excelizeam) applyUpdate(u cellUpdate) error {
 	var styleID int
-	if s, ok := e.styleStore.Load(hash); ok {
-		styleID = s.(StoredStyle).StyleID
-	} else {
+	if u.style != nil {
 		var err error
-		styleID, err = e.file.NewStyle(&styl)
+		styleID, err = e.wb.getStyleIDByKey(u.styleKey, u.style)
 		if err != nil {
-			return 0, err
+			return newCellError(u.op, u.colIndex, u.rowIndex, err)
 		}
-		e.styleStore.Store(hash, StoredStyle{
-			StyleID: styleID,
-			Style:   style,
+	}
+	err := e.cellStore.Update(u.colIndex, u.rowIndex, func(cell *Cell, exists bool) error {
+		if u.value != nil {
+			if exists && cell.Value != nil {
+				value, err := u.valuePolicy.overrideValue(cell.Value, u.value)
+				if err != nil {
+					return err
+				}
+				cell.Value = value
+			} else {
+				cell.Value = u.value
+			}
+		}
+		if u.style == nil {
+			return nil
+		}
+		if exists && cell.StyleID > 0 {
+			overrideStyleID, err := e.wb.overrideStyle(cell.StyleID, *u.style, u.stylePolicy)
+			if err != nil {
+				return err
+			}
+			cell.StyleID = overrideStyleID
+			return nil
+		}
+		cell.StyleID = styleID
+		return nil
+	})
+	if err != nil {
+		return newCellError(u.op, u.colIndex, u.rowIndex, err)
+	}
+	if u.hyperlink != nil {
+		cellName, err := excelize.CoordinatesToCellName(u.colIndex, u.rowIndex)
+		if err != nil {
+			return newCellError(u.op, u.colIndex, u.rowIndex, err)
+		}
+		if err := e.wb.setCellHyperlink(e.Name(), cellName, *u.hyperlink); err != nil {
+			return newCellError(u.op, u.colIndex, u.rowIndex, err)
+		}
+	}
+	return nil
+}
+
+// addPendingUpdate Hold the updates of the asynchronous operation with the sequence number,
+// which are applied in the order of the operations by applyPendingUpdates
+func (e *excelizeam) addPendingUpdate(seq uint64) applyUpdateFunc {
+	prevChunkIdx := -1
+	return func(u cellUpdate) error {
+		if err := e.cellStore.AddPending(seq, u); err != nil {
+			return newCellError(u.op, u.colIndex, u.rowIndex, err)
+		}
+		chunkIdx, _ := chunkIndex(u.rowIndex)
+		if prevChunkIdx >= 0 && prevChunkIdx != chunkIdx {
+			e.cellStore.AddSpan(seq, prevChunkIdx, chunkIdx)
+		}
+		prevChunkIdx = chunkIdx
+		return nil
+	}
+}
+
+// applyPendingUpdates Apply the pending updates of the asynchronous operations up to lastSeq in the order of the operations
+// Chunks never share a cell, so the updates of each chunk are applied in order while the chunks are applied in parallel.
+// Unless wait is true, the chunks being applied by another goroutine are left to it or to a later call.
+func (e *excelizeam) applyPendingUpdates(lastSeq uint64, wait bool) error {
+	apply := func(chunkIdx int, updates []pendingUpdate) ([]pendingUpdate, error) {
+		return e.applyChunkUpdates(chunkIdx, updates, wait)
+	}
+	chunks := e.cellStore.PendingChunks()
+	if !wait || len(chunks) <= 1 {
+		for _, chunk := range chunks {
+			if err := e.cellStore.ApplyPending(chunk, lastSeq, wait, apply); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	var eg errgroup.Group
+	eg.SetLimit(runtime.GOMAXPROCS(0))
+	for _, chunk := range chunks {
+		eg.Go(func() error {
+			return e.cellStore.ApplyPending(chunk, lastSeq, wait, apply)
 		})
 	}
-	return styleID, nil
+	return eg.Wait()
+}
+
+// applyChunkUpdates Apply the pending updates of a chunk sorted in the order of the operations and get the updates left for later
+// Like the synchronous methods, the remaining updates of an operation are skipped once one of them fails,
+// and an operation spanning chunks is applied to them in row order, see cellStore.WaitSpan.
+func (e *excelizeam) applyChunkUpdates(chunkIdx int, updates []pendingUpdate, wait bool) ([]pendingUpdate, error) {
+	for i, n := 0, 0; i < len(updates); n++ {
+		if n%cellChunkRows == 0 {
+			if err := e.wb.ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		seq := updates[i].seq
+		end := i + 1
+		for end < len(updates) && updates[end].seq == seq {
+			end++
+		}
+		span, ready, skip, err := e.cellStore.WaitSpan(e.wb.ctx, seq, chunkIdx, wait)
+		if err != nil {
+			return nil, err
+		}
+		if span && !ready {
+			// the operation has updates in a previous chunk not applied yet
+			return updates[i:], nil
+		}
+		failed := skip
+		for _, u := range updates[i:end] {
+			if failed {
+				break
+			}
+			if err := e.applyUpdate(u.cellUpdate); err != nil {
+				e.async.addError(u.seq, err)
+				failed = true
+			}
+		}
+		if span {
+			e.cellStore.DoneSpan(seq, chunkIdx, failed)
+		}
+		i = end
+	}
+	return nil, nil
 }
 
-func (e *excelizeam) overrideStyle(originStyleID int, overrideStyle excelize.Style) (int, error) {
-	var originStyle *excelize.Style
-	e.styleStore.Range(func(_, value any) bool {
-		if value.(StoredStyle).StyleID == originStyleID {
-			originStyle = value.(StoredStyle).Style
-			return false
+func (wb *workbook) getStyleID(style *excelize.Style) (int, error) {
+	if style == nil {
+		return 0, nil
+	}
+	key, err := styleKey(style)
+	if err != nil {
+		return 0, err
+	}
+	return wb.getStyleIDByKey(key, style)
+}
+
+// getStyleIDByKey Get the style ID of the style whose canonical key is already known
+func (wb *workbook) getStyleIDByKey(key [sha1.Size]byte, style *excelize.Style) (int, error) {
+	return wb.styleStore.LoadOrStore(key, func() (StoredStyle, error) {
+		styl := copyStyle(style)
+		styleID, err := wb.file.NewStyle(styl)
+		if err != nil {
+			return StoredStyle{}, err
 		}
-		return true
+		return StoredStyle{
+			StyleID: styleID,
+			Style:   styl,
+		}, nil
 	})
-	if originStyle == nil {
-		return e.getStyleID(&overrideStyle)
+}
+
+// overrideStyle Get the style ID of the style set to a cell with originStyleID by the policy
+func (wb *workbook) overrideStyle(originStyleID int, overrideStyle excelize.Style, policy OverridePolicy) (int, error) {
+	originStyle, ok := wb.styleStore.Load(originStyleID)
+	if !ok {
+		originStyle = &excelize.Style{}
+	}
+	style, err := policy.overrideStyle(originStyle, &overrideStyle)
+	if err != nil {
+		return 0, err
+	}
+	if style == originStyle {
+		return originStyleID, nil
 	}
+	return wb.getStyleID(style)
+}
 
+// mergeStyle Merge the fields set in overrideStyle into originStyle
+func mergeStyle(originStyle, overrideStyle *excelize.Style) *excelize.Style {
 	style := new(excelize.Style)
 	style.Fill = originStyle.Fill
 	style.Alignment = originStyle.Alignment
@@ -641,139 +1100,239 @@ func (e *excelizeam) overrideStyle(originStyleID int, overrideStyle excelize.Sty
 		style.Protection = overrideStyle.Protection
 	}
 
-	return e.getStyleID(style)
+	return style
 }
 
-func (e *excelizeam) getCacheKey(colIndex, rowIndex int) string {
-	return fmt.Sprintf("%d-%d", rowIndex, colIndex)
+func (e *excelizeam) SetOverridePolicy(policy OverridePolicy) {
+	if policy.isDefault() {
+		policy = OverridePolicyError
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.defaultPolicy = policy
 }
 
-func (e *excelizeam) getCacheAddress(key string) (colIndex, rowIndex int) {
-	indexes := strings.Split(key, "-")
-	if len(indexes) == 2 {
-		var err error
-		if rowIndex, err = strconv.Atoi(indexes[0]); err != nil {
-			return 0, 0
-		}
-		if colIndex, err = strconv.Atoi(indexes[1]); err != nil {
-			return 0, 0
-		}
+// overridePolicy Get the policy to be used for the policy passed to a *WithPolicy method
+func (e *excelizeam) overridePolicy(policy OverridePolicy) OverridePolicy {
+	if !policy.isDefault() {
+		return policy
 	}
-	return colIndex, rowIndex
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.defaultPolicy
+}
+
+// checkFinished Get the error returned by the methods changing the sheet once it has been written or closed
+func (e *excelizeam) checkFinished() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.finishedErr
 }
 
-func (e *excelizeam) checkMaxIndex(colIndex, rowIndex int) {
+func (e *excelizeam) checkMaxIndex(startRowIndex, colIndex, rowIndex int) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
+	if e.finishedErr != nil {
+		return e.finishedErr
+	}
+	if startRowIndex <= e.flushedRow {
+		return &RowFlushedError{RowIndex: startRowIndex, FlushedRowIndex: e.flushedRow}
+	}
 	if e.maxCol < colIndex {
 		e.maxCol = colIndex
 	}
 	if e.maxRow < rowIndex {
 		e.maxRow = rowIndex
 	}
+	return nil
 }
 
-func (e *excelizeam) Wait() error {
-	return e.eg.Wait()
-}
-
-func (e *excelizeam) Write(w io.Writer) error {
-	if err := e.writeStream(); err != nil {
-		return err
+func (e *excelizeam) SetAsyncLimit(workers, batchSize int) error {
+	if workers < 1 || batchSize < 1 {
+		return ErrInvalidAsyncLimit
 	}
-	if err := e.sw.Flush(); err != nil {
+	if err := e.async.Wait(); err != nil {
 		return err
 	}
-	if err := e.file.Write(w); err != nil {
-		return err
+	e.async.setLimit(workers, batchSize)
+	return nil
+}
+
+func (e *excelizeam) SetAsyncErrorLimit(limit int) error {
+	if limit < 0 {
+		return ErrInvalidAsyncLimit
 	}
+	e.async.setErrorLimit(limit)
 	return nil
 }
 
+func (e *excelizeam) StyleByID(styleID int) (*excelize.Style, bool) {
+	return e.wb.StyleByID(styleID)
+}
+
+func (e *excelizeam) StyleIDFromCell(sheet, cell string) (int, error) {
+	return e.wb.StyleIDFromCell(sheet, cell)
+}
+
+func (e *excelizeam) Wait() error {
+	return e.async.Wait()
+}
+
+func (e *excelizeam) Write(w io.Writer) error {
+	return e.wb.Write(w)
+}
+
 func (e *excelizeam) File() (*excelize.File, error) {
-	if err := e.writeStream(); err != nil {
-		return nil, err
-	}
-	if err := e.sw.Flush(); err != nil {
-		return nil, err
-	}
-	return e.file, nil
+	return e.wb.File()
+}
+
+func (e *excelizeam) Close() error {
+	return e.wb.Close()
 }
 
 func (e *excelizeam) CSVRecords() ([][]string, error) {
-	if err := e.eg.Wait(); err != nil {
+	if err := e.async.Wait(); err != nil {
 		return nil, err
 	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.flushedRow > 0 {
+		return nil, &RowFlushedError{RowIndex: 1, FlushedRowIndex: e.flushedRow}
+	}
 	records := make([][]string, e.maxRow)
 	for i := 0; i < e.maxRow; i++ {
 		records[i] = make([]string, e.maxCol)
 	}
 
-	e.cellStore.Range(func(k, cached any) bool {
-		key := k.(string)
-		c := cached.(*Cell)
-		colIdx, rowIdx := e.getCacheAddress(key)
-		if c.Value != nil {
-			records[rowIdx-1][colIdx-1] = fmt.Sprintf("%v", c.Value)
-		}
-		return true
-	})
+	for rowIdx := 1; rowIdx <= e.maxRow; rowIdx++ {
+		e.cellStore.RangeRow(rowIdx, func(colIdx int, c Cell) {
+			if c.Value != nil {
+				records[rowIdx-1][colIdx-1] = csvValue(c.Value, e.csvFormulaMode)
+			}
+		})
+	}
 	return records, nil
 }
 
+func (e *excelizeam) FlushRows(rowIndex int) error {
+	if err := e.async.Wait(); err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.finishedErr != nil {
+		return e.finishedErr
+	}
+	return e.flushRows(rowIndex, true)
+}
+
+// writeStream Write the remaining rows to the StreamWriter, after which the sheet can no longer be changed
 func (e *excelizeam) writeStream() error {
-	if err := e.eg.Wait(); err != nil {
+	if err := e.async.Wait(); err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.finishedErr = ErrAlreadyWritten
+	if err := e.flushRows(e.maxRow, false); err != nil {
 		return err
 	}
-	defaultStyleCells := make([]interface{}, e.maxCol)
+	if e.table != nil {
+		return e.sw.AddTable(e.table)
+	}
+	return nil
+}
+
+// finish Stop the sheet from being changed with err
+func (e *excelizeam) finish(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.finishedErr = err
+}
+
+// flushRows writes the rows after the current watermark up to rowIndex to the StreamWriter.
+// When release is true, the written cells are removed from the cellStore and the watermark is moved to rowIndex.
+// e.mu must be held by the caller.
+func (e *excelizeam) flushRows(rowIndex int, release bool) error {
+	if e.panes != nil {
+		if err := e.sw.SetPanes(e.panes); err != nil {
+			return err
+		}
+		e.panes = nil
+	}
+	if rowIndex <= e.flushedRow {
+		return nil
+	}
+	if release {
+		// updates of operations which have checked the rows before the flush fail from here on instead of being lost
+		e.cellStore.ReleaseRows(rowIndex)
+	}
+	colCount := e.maxCol
+	if e.defaultBorder != nil && colCount < e.defaultBorderCols {
+		colCount = e.defaultBorderCols
+	}
+	defaultStyleCells := make([]interface{}, colCount)
 	if e.defaultBorder != nil {
-		for i := 0; i < e.maxCol; i++ {
+		for i := 0; i < colCount; i++ {
 			defaultStyleCells[i] = excelize.Cell{StyleID: e.defaultBorder.StyleID, Value: ""}
 		}
 	}
 
-	type writeCols struct {
-		Cols     []interface{}
-		CanWrite bool
+	lastRowIdx := rowIndex
+	if lastRowIdx > e.maxRow {
+		lastRowIdx = e.maxRow
 	}
-
-	writeRows := make([]writeCols, e.maxRow)
-	for i := 0; i < e.maxRow; i++ {
-		rowIdx := i + 1
-		writeRows[i] = writeCols{
-			Cols: make([]interface{}, e.maxCol),
+	for rowIdx := e.flushedRow + 1; rowIdx <= lastRowIdx; rowIdx++ {
+		if err := e.wb.ctx.Err(); err != nil {
+			return err
 		}
+		cols := make([]interface{}, colCount)
+		canWrite := false
 		if e.defaultBorder != nil {
-			copy(writeRows[i].Cols, defaultStyleCells)
-			writeRows[i].CanWrite = true
+			copy(cols, defaultStyleCells)
+			canWrite = true
 		}
-		for ii := 0; ii < e.maxCol; ii++ {
-			colIdx := ii + 1
-			cached, ok := e.cellStore.Load(e.getCacheKey(colIdx, rowIdx))
-			if !ok {
-				continue
+		e.cellStore.RangeRow(rowIdx, func(colIdx int, c Cell) {
+			cols[colIdx-1] = streamCell(c)
+			canWrite = true
+			if f, ok := c.Value.(Formula); ok && f.Array {
+				if e.arrayFormulas == nil {
+					e.arrayFormulas = make(map[string]string)
+				}
+				cellName, _, _ := strings.Cut(f.Ref, ":")
+				e.arrayFormulas[cellName] = f.Ref
 			}
-			c := cached.(*Cell)
-			writeRows[i].Cols[ii] = excelize.Cell{StyleID: c.StyleID, Value: c.Value}
-			writeRows[i].CanWrite = true
+		})
+		var rowOpts []excelize.RowOpts
+		if ro, ok := e.rowOptions[rowIdx]; ok {
+			rowOpts = append(rowOpts, ro.opts)
+			canWrite = true
 		}
-	}
-
-	for i, row := range writeRows {
-		if !row.CanWrite {
+		if !canWrite {
 			continue
 		}
 
-		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		cell, err := excelize.CoordinatesToCellName(1, rowIdx)
 		if err != nil {
 			return err
 		}
 		if err := e.sw.SetRow(
 			cell,
-			row.Cols,
+			cols,
+			rowOpts...,
 		); err != nil {
 			return err
 		}
+		e.rowWritten = true
+	}
+	if release {
+		e.cellStore.DeleteRows(e.flushedRow+1, rowIndex)
+		for rowIdx := range e.rowOptions {
+			if rowIdx <= rowIndex {
+				delete(e.rowOptions, rowIdx)
+			}
+		}
+		e.flushedRow = rowIndex
 	}
 	return nil
 }
@@ -0,0 +1,261 @@
+package excelizeam
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"errors"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/tomtwinkle/excelizeam/excelizestyle"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestStyleKey(t *testing.T) {
+	t.Parallel()
+	newStyle := func(size float64) *excelize.Style {
+		decimalPlaces := 2
+		return &excelize.Style{
+			Border: []excelize.Border{
+				excelizestyle.Border(excelizestyle.BorderPositionTop, excelizestyle.BorderStyleContinuous2, excelizestyle.BorderColorBlack),
+				excelizestyle.Border(excelizestyle.BorderPositionLeft, excelizestyle.BorderStyleDash2, excelizestyle.BorderColorBlack),
+			},
+			Font:          &excelize.Font{Bold: true, Size: size},
+			Alignment:     excelizestyle.Alignment(excelizestyle.AlignmentHorizontalCenter, excelizestyle.AlignmentVerticalCenter, true),
+			DecimalPlaces: &decimalPlaces,
+		}
+	}
+	tests := map[string]struct {
+		style1    *excelize.Style
+		style2    *excelize.Style
+		wantEqual bool
+	}{
+		"equal_values_different_pointers": {
+			style1:    newStyle(12),
+			style2:    newStyle(12),
+			wantEqual: true,
+		},
+		"equal_values_different_border_order": {
+			style1: newStyle(12),
+			style2: func() *excelize.Style {
+				s := newStyle(12)
+				s.Border[0], s.Border[1] = s.Border[1], s.Border[0]
+				return s
+			}(),
+			wantEqual: true,
+		},
+		"different_font_size": {
+			style1:    newStyle(12),
+			style2:    newStyle(13),
+			wantEqual: false,
+		},
+		"nil_font_and_empty_font": {
+			style1:    &excelize.Style{},
+			style2:    &excelize.Style{Font: &excelize.Font{}},
+			wantEqual: false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			key1, err := styleKey(tt.style1)
+			assert.NilError(t, err)
+			key2, err := styleKey(tt.style2)
+			assert.NilError(t, err)
+			assert.Equal(t, tt.wantEqual, key1 == key2)
+		})
+	}
+}
+
+func TestExcelizeam_StyleInterning_Store(t *testing.T) {
+	t.Parallel()
+	newStyle := func() *excelize.Style {
+		decimalPlaces := 2
+		return &excelize.Style{
+			Border: []excelize.Border{
+				excelizestyle.Border(excelizestyle.BorderPositionTop, excelizestyle.BorderStyleContinuous2, excelizestyle.BorderColorBlack),
+			},
+			Font:          &excelize.Font{Bold: true, Size: 8},
+			Alignment:     excelizestyle.Alignment(excelizestyle.AlignmentHorizontalCenter, excelizestyle.AlignmentVerticalCenter, true),
+			DecimalPlaces: &decimalPlaces,
+		}
+	}
+	tests := map[string]struct {
+		testFunc func(w Excelizeam) error
+	}{
+		"SetCellValue": {
+			testFunc: func(w Excelizeam) error {
+				for rowIdx := 1; rowIdx <= 10; rowIdx++ {
+					for colIdx := 1; colIdx <= 10; colIdx++ {
+						if err := w.SetCellValue(colIdx, rowIdx, rowIdx*colIdx, newStyle(), false, false); err != nil {
+							return err
+						}
+					}
+				}
+				return nil
+			},
+		},
+		"SetCellValueAsync": {
+			testFunc: func(w Excelizeam) error {
+				for rowIdx := 1; rowIdx <= 10; rowIdx++ {
+					for colIdx := 1; colIdx <= 10; colIdx++ {
+						w.SetCellValueAsync(colIdx, rowIdx, rowIdx*colIdx, newStyle(), false)
+					}
+				}
+				return w.Wait()
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := New("test")
+			assert.NilError(t, err)
+			assert.NilError(t, tt.testFunc(w))
+			e := w.(*excelizeam)
+
+			// equal styles made anew for each cell are interned once
+			assert.Equal(t, 1, len(e.wb.styleStore.byKey))
+			assert.Equal(t, 1, len(e.wb.styleStore.byID))
+			wantCell, ok := e.cellStore.Load(1, 1)
+			assert.Assert(t, ok)
+			for rowIdx := 1; rowIdx <= 10; rowIdx++ {
+				for colIdx := 1; colIdx <= 10; colIdx++ {
+					cell, ok := e.cellStore.Load(colIdx, rowIdx)
+					assert.Assert(t, ok)
+					assert.Equal(t, wantCell.StyleID, cell.StyleID)
+				}
+			}
+		})
+	}
+}
+
+func TestExcelizeam_AsyncOrder_FailedRange(t *testing.T) {
+	t.Parallel()
+	style := excelize.Style{Fill: excelizestyle.Fill(excelizestyle.FillPatternSolid, "#FF0000")}
+	rangeStyle := excelize.Style{Fill: excelizestyle.Fill(excelizestyle.FillPatternSolid, "#00FF00")}
+	borderRange := BorderRange{
+		Inside: &BorderItem{Style: excelizestyle.BorderStyleContinuous2, Color: excelizestyle.BorderColorBlack},
+	}
+	write := func(t *testing.T, async bool) *excelizeam {
+		w, err := New("test")
+		assert.NilError(t, err)
+		assert.NilError(t, w.SetAsyncLimit(8, 1))
+		// the ranges below fail on these cells, which are in the second and the third chunk
+		assert.NilError(t, w.SetStyleCell(2, 100, style, false))
+		assert.NilError(t, w.SetStyleCell(5, 150, style, false))
+		if async {
+			w.SetStyleCellRangeAsync(1, 1, 3, 300, rangeStyle, false)
+			w.SetBorderRangeAsync(4, 1, 6, 300, borderRange, false)
+			w.SetCellValueAsync(1, 200, "after", nil, false)
+			assert.Assert(t, errors.Is(w.Wait(), ErrOverrideCellStyle))
+		} else {
+			assert.Assert(t, errors.Is(w.SetStyleCellRange(1, 1, 3, 300, rangeStyle, false), ErrOverrideCellStyle))
+			assert.Assert(t, errors.Is(w.SetBorderRange(4, 1, 6, 300, borderRange, false), ErrOverrideCellStyle))
+			assert.NilError(t, w.SetCellValue(1, 200, "after", nil, false, false))
+		}
+		return w.(*excelizeam)
+	}
+	cellStyleKey := func(t *testing.T, e *excelizeam, colIdx, rowIdx int) ([sha1.Size]byte, interface{}) {
+		cell, ok := e.cellStore.Load(colIdx, rowIdx)
+		if !ok || cell.StyleID == 0 {
+			return [sha1.Size]byte{}, cell.Value
+		}
+		style, ok := e.wb.styleStore.Load(cell.StyleID)
+		assert.Assert(t, ok)
+		key, err := styleKey(style)
+		assert.NilError(t, err)
+		return key, cell.Value
+	}
+
+	want := write(t, false)
+	for cell, wantStyled := range map[[2]int]bool{{3, 99}: true, {1, 100}: true, {3, 100}: false, {1, 101}: false, {6, 149}: true, {4, 150}: true, {6, 150}: false, {4, 151}: false} {
+		c, _ := want.cellStore.Load(cell[0], cell[1])
+		assert.Equal(t, wantStyled, c.StyleID > 0, cell)
+	}
+	for i := 0; i < 5; i++ {
+		got := write(t, true)
+		for rowIdx := 1; rowIdx <= 300; rowIdx++ {
+			for colIdx := 1; colIdx <= 6; colIdx++ {
+				wantKey, wantValue := cellStyleKey(t, want, colIdx, rowIdx)
+				gotKey, gotValue := cellStyleKey(t, got, colIdx, rowIdx)
+				assert.Equal(t, wantKey, gotKey, "run %d col %d row %d", i, colIdx, rowIdx)
+				assert.Equal(t, wantValue, gotValue, "run %d col %d row %d", i, colIdx, rowIdx)
+			}
+		}
+	}
+}
+
+func TestExcelizeam_FlushRows_PendingUpdate(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		rowIndex    int
+		flushRowIdx int
+	}{
+		"released_chunk": {
+			rowIndex:    10,
+			flushRowIdx: cellChunkRows,
+		},
+		"released_rows_of_chunk": {
+			rowIndex:    10,
+			flushRowIdx: 20,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := New("test")
+			assert.NilError(t, err)
+			e := w.(*excelizeam)
+			// the update of an operation which has checked its row before the flush and is applied after it
+			assert.NilError(t, e.setCellValue("SetCellValueAsync", 1, tt.rowIndex, "test", nil, OverridePolicyError, OverridePolicyError, e.addPendingUpdate(1)))
+			assert.NilError(t, e.FlushRows(tt.flushRowIdx))
+
+			w.SetCellValueAsync(1, tt.flushRowIdx+1, "next", nil, false)
+			err = w.Wait()
+			var flushedErr *RowFlushedError
+			assert.Assert(t, errors.As(err, &flushedErr))
+			assert.Equal(t, tt.rowIndex, flushedErr.RowIndex)
+			assert.Equal(t, tt.flushRowIdx, flushedErr.FlushedRowIndex)
+			_, ok := e.cellStore.Load(1, tt.rowIndex)
+			assert.Assert(t, !ok)
+		})
+	}
+}
+
+func TestArrayFormulaWriter(t *testing.T) {
+	t.Parallel()
+	const sheet = `<sheetData><row r="1"><c r="A1" t="str"><f>A2:A3*2</f><v>2</v></c>` +
+		`<c r="B1" t="str"><f>B2*2</f></c><c r="C1" s="1" t="str"><f>C2:C3&amp;&quot;&gt;&quot;</f></c></row></sheetData>`
+	const want = `<sheetData><row r="1"><c r="A1" t="str"><f t="array" ref="A1:A2">A2:A3*2</f><v>2</v></c>` +
+		`<c r="B1" t="str"><f>B2*2</f></c><c r="C1" s="1" t="str"><f t="array" ref="C1">C2:C3&amp;&quot;&gt;&quot;</f></c></row></sheetData>`
+	tests := map[string]struct {
+		chunkSize int
+	}{
+		"whole":   {chunkSize: len(sheet)},
+		"byte":    {chunkSize: 1},
+		"cut_off": {chunkSize: 7},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			zw := &arrayFormulaZipWriter{arrayFormulas: map[string]map[string]string{
+				"sheet": {"A1": "A1:A2", "C1": "C1"},
+			}}
+			zw.w = &arrayFormulaWriter{w: &buf, refs: zw.arrayFormulas["sheet"]}
+			for i := 0; i < len(sheet); i += tt.chunkSize {
+				n, err := zw.w.Write([]byte(sheet[i:min(i+tt.chunkSize, len(sheet))]))
+				assert.NilError(t, err)
+				assert.Equal(t, min(tt.chunkSize, len(sheet)-i), n)
+			}
+			assert.NilError(t, zw.flush())
+			assert.Equal(t, want, buf.String())
+		})
+	}
+}
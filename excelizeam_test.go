@@ -1,10 +1,18 @@
 package excelizeam_test
 
 import (
+	"archive/zip"
 	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"path/filepath"
+	"runtime"
+	"sync"
 	"testing"
+	"time"
 
 	"gotest.tools/assert"
 
@@ -397,6 +405,14 @@ func TestExcelizeam_Async(t *testing.T) {
 			},
 			wantErr: excelizeam.ErrOverrideCellStyle,
 		},
+		"SetCellValueAsync-override_value": {
+			testFunc: func(w excelizeam.Excelizeam) {
+				w.SetCellValue(1, 1, "test1", &excelize.Style{Font: &excelize.Font{Size: 12}}, false, false)
+				// the last call wins
+				w.SetCellValueWithOverrideAsync(1, 1, "test2", nil, true, false)
+				w.SetCellValueWithOverrideAsync(1, 1, "test3", &excelize.Style{Font: &excelize.Font{Size: 13}}, true, true)
+			},
+		},
 		"SetCellValueAsync-with_not_style_multiple_rows_cols_no_sort": {
 			testFunc: func(w excelizeam.Excelizeam) {
 				for rowIdx := 1; rowIdx <= 10; rowIdx++ {
@@ -626,204 +642,2776 @@ func TestExcelizeam_CSVRecords(t *testing.T) {
 	}
 }
 
-func BenchmarkExcelizeam(b *testing.B) {
-	b.Run("Excelize", func(b *testing.B) {
-		var buf bytes.Buffer
-		defer buf.Reset()
+func TestExcelizeam_FlushRows(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		testFunc    func(w excelizeam.Excelizeam) error
+		wantRecords [][]string
+		wantErr     error
+	}{
+		"FlushRows-append_after_flush": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				for rowIdx := 1; rowIdx <= 4; rowIdx++ {
+					for colIdx := 1; colIdx <= 3; colIdx++ {
+						w.SetCellValueAsync(colIdx, rowIdx, fmt.Sprintf("test%d-%d", rowIdx, colIdx), nil, false)
+					}
+					if rowIdx%2 == 0 {
+						if err := w.FlushRows(rowIdx); err != nil {
+							return err
+						}
+					}
+				}
+				return w.SetCellValue(2, 5, "test5-2", nil, false, false)
+			},
+			wantRecords: [][]string{
+				{"test1-1", "test1-2", "test1-3"},
+				{"test2-1", "test2-2", "test2-3"},
+				{"test3-1", "test3-2", "test3-3"},
+				{"test4-1", "test4-2", "test4-3"},
+				{"", "test5-2", ""},
+			},
+		},
+		"FlushRows-set_cell_value_flushed_row_error": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				if err := w.SetCellValue(1, 1, "test1", nil, false, false); err != nil {
+					return err
+				}
+				if err := w.FlushRows(2); err != nil {
+					return err
+				}
+				return w.SetCellValue(1, 2, "test2", nil, false, false)
+			},
+			wantErr: excelizeam.ErrRowFlushed,
+		},
+		"FlushRows-set_border_range_flushed_row_error": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				if err := w.SetCellValue(1, 3, "test1", nil, false, false); err != nil {
+					return err
+				}
+				if err := w.FlushRows(3); err != nil {
+					return err
+				}
+				w.SetBorderRangeAsync(1, 3, 3, 5, excelizeam.BorderRange{
+					Top: &excelizeam.BorderItem{Style: excelizestyle.BorderStyleDash2, Color: excelizestyle.BorderColorBlack},
+				}, false)
+				return w.Wait()
+			},
+			wantErr: excelizeam.ErrRowFlushed,
+		},
+	}
 
-		b.ResetTimer()
-		for i := 0; i < b.N; i++ {
-			if err := benchExcelize(&buf); err != nil {
-				b.Error(err)
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := excelizeam.New("test")
+			assert.NilError(t, err)
+			err = tt.testFunc(w)
+			if tt.wantErr != nil {
+				assert.Assert(t, errors.Is(err, tt.wantErr))
+				var flushedErr *excelizeam.RowFlushedError
+				assert.Assert(t, errors.As(err, &flushedErr))
+				return
 			}
-		}
-	})
-	b.Run("Excelize Async", func(b *testing.B) {
-		var buf bytes.Buffer
-		defer buf.Reset()
+			assert.NilError(t, err)
+			var buf bytes.Buffer
+			err = w.Write(&buf)
+			assert.NilError(t, err)
 
-		b.ResetTimer()
-		for i := 0; i < b.N; i++ {
-			if err := benchExcelizeAsync(&buf); err != nil {
-				b.Error(err)
+			actual, err := excelize.OpenReader(&buf)
+			assert.NilError(t, err)
+			for rowIdx, row := range tt.wantRecords {
+				for colIdx, want := range row {
+					cell, err := excelize.CoordinatesToCellName(colIdx+1, rowIdx+1)
+					assert.NilError(t, err)
+					value, err := actual.GetCellValue("test", cell)
+					assert.NilError(t, err)
+					assert.Equal(t, want, value)
+				}
 			}
-		}
-	})
-	b.Run("Excelize StreamWriter", func(b *testing.B) {
-		var buf bytes.Buffer
-		defer buf.Reset()
+		})
+	}
+}
 
-		b.ResetTimer()
-		for i := 0; i < b.N; i++ {
-			if err := benchStream(&buf); err != nil {
-				b.Error(err)
-			}
-		}
-	})
-	b.Run("Excelizeam Sync", func(b *testing.B) {
-		var buf bytes.Buffer
-		defer buf.Reset()
+func TestExcelizeam_SetDefaultBorderColumns(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		colCount     int
+		wantBordered map[string]bool
+		wantErr      error
+	}{
+		"unset": {
+			wantBordered: map[string]bool{"B1": false, "C1": false, "A2": true, "B2": true},
+		},
+		"fixed_before_flush": {
+			colCount:     3,
+			wantBordered: map[string]bool{"B1": true, "C1": true, "A2": true, "B2": true},
+		},
+		"narrower_than_cells": {
+			colCount:     2,
+			wantBordered: map[string]bool{"B1": true, "C1": false, "A2": true, "B2": true},
+		},
+		"invalid": {
+			colCount: -1,
+			wantErr:  excelize.ErrColumnNumber,
+		},
+	}
 
-		b.ResetTimer()
-		for i := 0; i < b.N; i++ {
-			if err := benchExcelizeam(&buf); err != nil {
-				b.Error(err)
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := excelizeam.New("test")
+			assert.NilError(t, err)
+			assert.NilError(t, w.SetDefaultBorderStyle(excelizestyle.BorderStyleContinuous1, excelizestyle.BorderColorBlack))
+			err = w.SetDefaultBorderColumns(tt.colCount)
+			if tt.wantErr != nil {
+				assert.Assert(t, errors.Is(err, tt.wantErr))
+				return
 			}
-		}
-	})
-	b.Run("Excelizeam Async", func(b *testing.B) {
-		var buf bytes.Buffer
-		defer buf.Reset()
+			assert.NilError(t, err)
+			assert.NilError(t, w.SetCellValue(1, 1, "flushed", nil, false, false))
+			assert.NilError(t, w.FlushRows(1))
+			assert.NilError(t, w.SetCellValue(3, 2, "wider", nil, false, false))
 
-		b.ResetTimer()
-		for i := 0; i < b.N; i++ {
-			if err := benchExcelizeamAsync(&buf); err != nil {
-				b.Error(err)
+			var buf bytes.Buffer
+			assert.NilError(t, w.Write(&buf))
+			f, err := excelize.OpenReader(&buf)
+			assert.NilError(t, err)
+			for cell, want := range tt.wantBordered {
+				styleID, err := f.GetCellStyle("test", cell)
+				assert.NilError(t, err)
+				style, err := f.GetStyle(styleID)
+				assert.NilError(t, err)
+				assert.Equal(t, want, len(style.Border) == 4, cell)
 			}
-		}
-	})
+		})
+	}
 }
 
-func benchExcelize(w io.Writer) error {
-	f := excelize.NewFile()
-	f.SetSheetName("Sheet1", "test")
-
-	for rowIdx := 1; rowIdx <= 1000; rowIdx++ {
-		for colIdx := 1; colIdx <= 10; colIdx++ {
-			cell, err := excelize.CoordinatesToCellName(colIdx, rowIdx)
-			if err != nil {
-				return err
-			}
-			if err := f.SetCellValue("test", cell, fmt.Sprintf("test%d-%d", rowIdx, colIdx)); err != nil {
-				return err
-			}
-			styleID, err := f.NewStyle(&excelize.Style{
-				Border:    excelizestyle.BorderAround(excelizestyle.BorderStyleContinuous2, excelizestyle.BorderColorBlack),
-				Font:      &excelize.Font{Size: 12, Bold: true},
-				Alignment: excelizestyle.Alignment(excelizestyle.AlignmentHorizontalCenter, excelizestyle.AlignmentVerticalCenter, true),
-			})
-			if err != nil {
-				return err
-			}
-			if err := f.SetCellStyle("test", cell, cell, styleID); err != nil {
-				return err
-			}
+func TestExcelizeam_StyleInterning(t *testing.T) {
+	t.Parallel()
+	newStyle := func() *excelize.Style {
+		decimalPlaces := 2
+		customNumFmt := "#,##0.00"
+		return &excelize.Style{
+			Border: []excelize.Border{
+				excelizestyle.Border(excelizestyle.BorderPositionTop, excelizestyle.BorderStyleContinuous2, excelizestyle.BorderColorBlack),
+				excelizestyle.Border(excelizestyle.BorderPositionBottom, excelizestyle.BorderStyleContinuous2, excelizestyle.BorderColorBlack),
+			},
+			Fill: excelizestyle.Fill(excelizestyle.FillPatternSolid, "#315D3C"),
+			Font: &excelize.Font{
+				Bold:  true,
+				Size:  8,
+				Color: "#718DDC",
+			},
+			Alignment:     excelizestyle.Alignment(excelizestyle.AlignmentHorizontalCenter, excelizestyle.AlignmentVerticalCenter, true),
+			Protection:    &excelize.Protection{Locked: true},
+			DecimalPlaces: &decimalPlaces,
+			CustomNumFmt:  &customNumFmt,
 		}
 	}
-	return f.Write(w)
-}
-
-func benchExcelizeAsync(w io.Writer) error {
-	f := excelize.NewFile()
-	f.SetSheetName("Sheet1", "test")
-
-	var eg errgroup.Group
-
-	for rowIdx := 1; rowIdx <= 1000; rowIdx++ {
-		rowIdx := rowIdx
-		for colIdx := 1; colIdx <= 10; colIdx++ {
-			colIdx := colIdx
-			eg.Go(func() error {
-				cell, err := excelize.CoordinatesToCellName(colIdx, rowIdx)
-				if err != nil {
-					return err
-				}
-				if err := f.SetCellValue("test", cell, fmt.Sprintf("test%d-%d", rowIdx, colIdx)); err != nil {
-					return err
+	tests := map[string]struct {
+		testFunc func(w excelizeam.Excelizeam) error
+	}{
+		"SetCellValue": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				for rowIdx := 1; rowIdx <= 10; rowIdx++ {
+					for colIdx := 1; colIdx <= 10; colIdx++ {
+						if err := w.SetCellValue(colIdx, rowIdx, rowIdx*colIdx, newStyle(), false, false); err != nil {
+							return err
+						}
+					}
 				}
-				styleID, err := f.NewStyle(&excelize.Style{
-					Border:    excelizestyle.BorderAround(excelizestyle.BorderStyleContinuous2, excelizestyle.BorderColorBlack),
-					Font:      &excelize.Font{Size: 12, Bold: true},
-					Alignment: excelizestyle.Alignment(excelizestyle.AlignmentHorizontalCenter, excelizestyle.AlignmentVerticalCenter, true),
-				})
-				if err != nil {
-					return err
+				return nil
+			},
+		},
+		"SetCellValueAsync": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				for rowIdx := 1; rowIdx <= 10; rowIdx++ {
+					for colIdx := 1; colIdx <= 10; colIdx++ {
+						w.SetCellValueAsync(colIdx, rowIdx, rowIdx*colIdx, newStyle(), false)
+					}
 				}
-				if err := f.SetCellStyle("test", cell, cell, styleID); err != nil {
-					return err
+				return w.Wait()
+			},
+		},
+		"SetStyleCell-border_order": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				for rowIdx := 1; rowIdx <= 10; rowIdx++ {
+					style := newStyle()
+					if rowIdx%2 == 0 {
+						style.Border[0], style.Border[1] = style.Border[1], style.Border[0]
+					}
+					if err := w.SetStyleCell(1, rowIdx, *style, false); err != nil {
+						return err
+					}
 				}
 				return nil
-			})
-		}
-	}
-
-	if err := eg.Wait(); err != nil {
-		return err
+			},
+		},
 	}
-	return f.Write(w)
-}
 
-func benchStream(w io.Writer) error {
-	f := excelize.NewFile()
-	f.SetSheetName("Sheet1", "test")
-	sw, err := f.NewStreamWriter("test")
-	if err != nil {
-		return err
-	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := excelizeam.New("test")
+			assert.NilError(t, err)
+			err = tt.testFunc(w)
+			assert.NilError(t, err)
+			var buf bytes.Buffer
+			err = w.Write(&buf)
+			assert.NilError(t, err)
 
-	for rowIdx := 1; rowIdx <= 1000; rowIdx++ {
-		for colIdx := 1; colIdx <= 10; colIdx++ {
-			cell, err := excelize.CoordinatesToCellName(colIdx, rowIdx)
-			if err != nil {
-				return err
-			}
-			styleID, err := f.NewStyle(&excelize.Style{
-				Border:    excelizestyle.BorderAround(excelizestyle.BorderStyleContinuous2, excelizestyle.BorderColorBlack),
-				Font:      &excelize.Font{Size: 12, Bold: true},
-				Alignment: excelizestyle.Alignment(excelizestyle.AlignmentHorizontalCenter, excelizestyle.AlignmentVerticalCenter, true),
-			})
-			if err != nil {
-				return err
+			zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			assert.NilError(t, err)
+			styles, err := zr.Open("xl/styles.xml")
+			assert.NilError(t, err)
+			defer styles.Close()
+			var styleSheet struct {
+				CellXfs struct {
+					Xf []struct{} `xml:"xf"`
+				} `xml:"cellXfs"`
 			}
-			if err := sw.SetRow(cell, []interface{}{
-				excelize.Cell{
-					StyleID: styleID,
-					Value:   fmt.Sprintf("test%d-%d", rowIdx, colIdx),
-				},
-			}); err != nil {
-				return err
+			err = xml.NewDecoder(styles).Decode(&styleSheet)
+			assert.NilError(t, err)
+			// default style + interned style
+			assert.Equal(t, 2, len(styleSheet.CellXfs.Xf))
+
+			f, err := excelize.OpenReader(bytes.NewReader(buf.Bytes()))
+			assert.NilError(t, err)
+			wantStyleID, err := f.GetCellStyle("test", "A1")
+			assert.NilError(t, err)
+			assert.Assert(t, wantStyleID > 0)
+			for rowIdx := 1; rowIdx <= 10; rowIdx++ {
+				styleID, err := f.GetCellStyle("test", fmt.Sprintf("A%d", rowIdx))
+				assert.NilError(t, err)
+				assert.Equal(t, wantStyleID, styleID)
 			}
-		}
+		})
 	}
-	return f.Write(w)
 }
 
-func benchExcelizeam(w io.Writer) error {
-	e, err := excelizeam.New("test")
-	if err != nil {
-		return err
+func TestExcelizeam_StyleInterning_SharedPointers(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		async bool
+	}{
+		"Sync":  {},
+		"Async": {async: true},
 	}
 
-	for rowIdx := 1; rowIdx <= 1000; rowIdx++ {
-		for colIdx := 1; colIdx <= 10; colIdx++ {
-			if err := e.SetCellValue(colIdx, rowIdx, fmt.Sprintf("test%d-%d", rowIdx, colIdx), &excelize.Style{
-				Border:    excelizestyle.BorderAround(excelizestyle.BorderStyleContinuous2, excelizestyle.BorderColorBlack),
-				Font:      &excelize.Font{Size: 12, Bold: true},
-				Alignment: excelizestyle.Alignment(excelizestyle.AlignmentHorizontalCenter, excelizestyle.AlignmentVerticalCenter, true),
-			}, false, false); err != nil {
-				return err
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := excelizeam.New("test")
+			assert.NilError(t, err)
+			// the caller reuses the same font for the cells
+			font := &excelize.Font{Size: 10}
+			if tt.async {
+				w.SetCellValueAsync(1, 1, "A1", &excelize.Style{Font: font}, false)
+				assert.NilError(t, w.Wait())
+			} else {
+				assert.NilError(t, w.SetCellValue(1, 1, "A1", &excelize.Style{Font: font}, false, false))
 			}
-		}
+			font.Size = 20
+			assert.NilError(t, w.SetCellValue(2, 1, "B1", &excelize.Style{Font: font}, false, false))
+			assert.NilError(t, w.SetStyleCell(1, 1, excelize.Style{NumFmt: 14}, true))
+
+			var buf bytes.Buffer
+			assert.NilError(t, w.Write(&buf))
+			f, err := excelize.OpenReader(&buf)
+			assert.NilError(t, err)
+			for cell, wantSize := range map[string]float64{"A1": 10, "B1": 20} {
+				styleID, err := f.GetCellStyle("test", cell)
+				assert.NilError(t, err)
+				style, err := f.GetStyle(styleID)
+				assert.NilError(t, err)
+				assert.Equal(t, wantSize, style.Font.Size, cell)
+			}
+		})
 	}
-	return e.Write(w)
 }
 
-func benchExcelizeamAsync(w io.Writer) error {
-	e, err := excelizeam.New("test")
-	if err != nil {
-		return err
+func TestExcelizeam_StyleByID(t *testing.T) {
+	t.Parallel()
+	w, err := excelizeam.New("test")
+	assert.NilError(t, err)
+	style := &excelize.Style{
+		Border: excelizestyle.BorderAround(excelizestyle.BorderStyleContinuous2, excelizestyle.BorderColorBlack),
+		Font:   &excelize.Font{Bold: true, Size: 8, Color: "#718DDC"},
 	}
+	err = w.SetCellValue(1, 1, "test", style, false, false)
+	assert.NilError(t, err)
+	err = w.SetBorderRange(1, 1, 2, 2, excelizeam.BorderRange{
+		Top: &excelizeam.BorderItem{Style: excelizestyle.BorderStyleDash2, Color: excelizestyle.BorderColorBlack},
+	}, true)
+	assert.NilError(t, err)
 
-	for rowIdx := 1; rowIdx <= 1000; rowIdx++ {
-		for colIdx := 1; colIdx <= 10; colIdx++ {
-			e.SetCellValueAsync(colIdx, rowIdx, fmt.Sprintf("test%d-%d", rowIdx, colIdx), &excelize.Style{
-				Border:    excelizestyle.BorderAround(excelizestyle.BorderStyleContinuous2, excelizestyle.BorderColorBlack),
-				Font:      &excelize.Font{Size: 12, Bold: true},
-				Alignment: excelizestyle.Alignment(excelizestyle.AlignmentHorizontalCenter, excelizestyle.AlignmentVerticalCenter, true),
-			}, false)
-		}
+	f, err := w.File()
+	assert.NilError(t, err)
+	var buf bytes.Buffer
+	err = f.Write(&buf)
+	assert.NilError(t, err)
+	actual, err := excelize.OpenReader(&buf)
+	assert.NilError(t, err)
+
+	styleID, err := actual.GetCellStyle("test", "A1")
+	assert.NilError(t, err)
+	got, ok := w.StyleByID(styleID)
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, style.Font, got.Font)
+	top, ok := excelizestyle.FindBorder(got.Border, excelizestyle.BorderPositionTop)
+	assert.Assert(t, ok)
+	assert.Equal(t, int(excelizestyle.BorderStyleDash2), top.Style)
+	left, ok := excelizestyle.FindBorder(got.Border, excelizestyle.BorderPositionLeft)
+	assert.Assert(t, ok)
+	assert.Equal(t, int(excelizestyle.BorderStyleContinuous2), left.Style)
+
+	// the returned style is a copy
+	got.Font.Size = 99
+	for i := range got.Border {
+		got.Border[i].Style = 0
 	}
+	again, ok := w.StyleByID(styleID)
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, style.Font, again.Font)
+	top, ok = excelizestyle.FindBorder(again.Border, excelizestyle.BorderPositionTop)
+	assert.Assert(t, ok)
+	assert.Equal(t, int(excelizestyle.BorderStyleDash2), top.Style)
 
+	_, ok = w.StyleByID(-1)
+	assert.Assert(t, !ok)
+}
+
+func TestExcelizeam_Context(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		testFunc func(w excelizeam.Excelizeam, cancel context.CancelFunc) error
+		wantErr  error
+	}{
+		"not_canceled": {
+			testFunc: func(w excelizeam.Excelizeam, cancel context.CancelFunc) error {
+				defer cancel()
+				w.SetCellValueAsync(1, 1, "test", nil, false)
+				return w.Write(io.Discard)
+			},
+		},
+		"canceled_before_async": {
+			testFunc: func(w excelizeam.Excelizeam, cancel context.CancelFunc) error {
+				cancel()
+				for rowIdx := 1; rowIdx <= 10; rowIdx++ {
+					w.SetCellValueAsync(1, rowIdx, "test", nil, false)
+				}
+				return w.Wait()
+			},
+			wantErr: context.Canceled,
+		},
+		"canceled_before_write": {
+			testFunc: func(w excelizeam.Excelizeam, cancel context.CancelFunc) error {
+				if err := w.SetCellValue(1, 1, "test", nil, false, false); err != nil {
+					return err
+				}
+				cancel()
+				return w.Write(io.Discard)
+			},
+			wantErr: context.Canceled,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx, cancel := context.WithCancel(context.Background())
+			w, err := excelizeam.NewWithContext(ctx, "test")
+			assert.NilError(t, err)
+			err = tt.testFunc(w, cancel)
+			if tt.wantErr != nil {
+				assert.Assert(t, errors.Is(err, tt.wantErr))
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
+
+func TestExcelizeam_SetAsyncLimit(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		workers   int
+		batchSize int
+		wantErr   error
+	}{
+		"single_worker_no_batch": {
+			workers:   1,
+			batchSize: 1,
+		},
+		"multiple_workers_batch": {
+			workers:   4,
+			batchSize: 7,
+		},
+		"invalid_workers": {
+			workers:   0,
+			batchSize: 1,
+			wantErr:   excelizeam.ErrInvalidAsyncLimit,
+		},
+		"invalid_batch_size": {
+			workers:   1,
+			batchSize: 0,
+			wantErr:   excelizeam.ErrInvalidAsyncLimit,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := excelizeam.New("test")
+			assert.NilError(t, err)
+			err = w.SetAsyncLimit(tt.workers, tt.batchSize)
+			if tt.wantErr != nil {
+				assert.ErrorContains(t, err, tt.wantErr.Error())
+				return
+			}
+			assert.NilError(t, err)
+
+			wantRecords := make([][]string, 50)
+			for rowIdx := 1; rowIdx <= 50; rowIdx++ {
+				wantRecords[rowIdx-1] = make([]string, 5)
+				for colIdx := 1; colIdx <= 5; colIdx++ {
+					value := fmt.Sprintf("test%d-%d", rowIdx, colIdx)
+					wantRecords[rowIdx-1][colIdx-1] = value
+					w.SetCellValueAsync(colIdx, rowIdx, value, nil, false)
+				}
+			}
+			records, err := w.CSVRecords()
+			assert.NilError(t, err)
+			assert.DeepEqual(t, wantRecords, records)
+		})
+	}
+}
+
+func TestExcelizeam_SetAsyncLimit_Concurrent(t *testing.T) {
+	t.Parallel()
+	w, err := excelizeam.New("test")
+	assert.NilError(t, err)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for rowIdx := 1; rowIdx <= 100; rowIdx++ {
+			for colIdx := 1; colIdx <= 5; colIdx++ {
+				w.SetCellValueAsync(colIdx, rowIdx, rowIdx*colIdx, nil, false)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 10; i++ {
+			assert.NilError(t, w.SetAsyncLimit(i%3+1, i))
+		}
+	}()
+	wg.Wait()
+
+	records, err := w.CSVRecords()
+	assert.NilError(t, err)
+	assert.Equal(t, 100, len(records))
+	assert.Equal(t, "500", records[99][4])
+}
+
+func TestExcelizeam_SetAsyncErrorLimit(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		setLimit   bool
+		limit      int
+		wantErrNum int
+		wantErr    error
+	}{
+		"default_first_error_only": {
+			wantErrNum: 1,
+		},
+		"limited": {
+			setLimit:   true,
+			limit:      3,
+			wantErrNum: 3,
+		},
+		"unlimited": {
+			setLimit:   true,
+			limit:      0,
+			wantErrNum: 10,
+		},
+		"invalid_limit": {
+			setLimit: true,
+			limit:    -1,
+			wantErr:  excelizeam.ErrInvalidAsyncLimit,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := excelizeam.New("test")
+			assert.NilError(t, err)
+			if tt.setLimit {
+				err = w.SetAsyncErrorLimit(tt.limit)
+				if tt.wantErr != nil {
+					assert.ErrorContains(t, err, tt.wantErr.Error())
+					return
+				}
+				assert.NilError(t, err)
+			}
+			for rowIdx := 1; rowIdx <= 10; rowIdx++ {
+				assert.NilError(t, w.SetCellValue(1, rowIdx, "test", nil, false, false))
+			}
+			for rowIdx := 1; rowIdx <= 10; rowIdx++ {
+				w.SetCellValueAsync(1, rowIdx, "override", nil, false)
+			}
+			err = w.Wait()
+			assert.Assert(t, errors.Is(err, excelizeam.ErrOverrideCellValue))
+
+			errs := []error{err}
+			if joined, ok := err.(interface{ Unwrap() []error }); ok {
+				errs = joined.Unwrap()
+			}
+			assert.Equal(t, tt.wantErrNum, len(errs))
+			for _, err := range errs {
+				var cellErr *excelizeam.CellError
+				assert.Assert(t, errors.As(err, &cellErr))
+				assert.Equal(t, "SetCellValueAsync", cellErr.Op)
+			}
+		})
+	}
+}
+
+func TestExcelizeam_AsyncOrder(t *testing.T) {
+	t.Parallel()
+	colors := []string{"FF0000", "00FF00", "0000FF", "FFFF00", "00FFFF", "FF00FF"}
+	write := func(async bool) ([]byte, error) {
+		w, err := excelizeam.New("test")
+		if err != nil {
+			return nil, err
+		}
+		if err := w.SetAsyncLimit(8, 1); err != nil {
+			return nil, err
+		}
+		for i := 0; i < 20; i++ {
+			style := excelize.Style{
+				Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{colors[i%len(colors)]}},
+				Font: &excelize.Font{Size: float64(10 + i%7)},
+			}
+			borderRange := excelizeam.BorderRange{
+				Top:    &excelizeam.BorderItem{Style: excelizestyle.BorderStyle(i%5 + 1), Color: excelizestyle.BorderColorBlack},
+				Inside: &excelizeam.BorderItem{Style: excelizestyle.BorderStyle(i%3 + 1), Color: excelizestyle.BorderColorBlack},
+			}
+			colIdx, rowIdx := i%4+1, i%5+1
+			if async {
+				w.SetStyleCellAsync(colIdx, rowIdx, style, true)
+				w.SetStyleCellRangeAsync(1, 1, 6, 6, style, true)
+				w.SetBorderRangeAsync(colIdx, rowIdx, colIdx+2, rowIdx+2, borderRange, true)
+				w.SetCellValueAsync(colIdx, rowIdx, nil, &style, true)
+				continue
+			}
+			if err := w.SetStyleCell(colIdx, rowIdx, style, true); err != nil {
+				return nil, err
+			}
+			if err := w.SetStyleCellRange(1, 1, 6, 6, style, true); err != nil {
+				return nil, err
+			}
+			if err := w.SetBorderRange(colIdx, rowIdx, colIdx+2, rowIdx+2, borderRange, true); err != nil {
+				return nil, err
+			}
+			if err := w.SetCellValue(colIdx, rowIdx, nil, &style, false, true); err != nil {
+				return nil, err
+			}
+		}
+		var buf bytes.Buffer
+		if err := w.Write(&buf); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	want, err := write(false)
+	assert.NilError(t, err)
+	for i := 0; i < 5; i++ {
+		got, err := write(true)
+		assert.NilError(t, err)
+		assert.Assert(t, bytes.Equal(want, got), "run %d differs from the synchronous output", i)
+	}
+}
+
+func TestExcelizeam_Contention(t *testing.T) {
+	t.Parallel()
+	const goroutines, calls = 16, 200
+	newStyle := func(i int) excelize.Style {
+		return excelize.Style{
+			Font:   &excelize.Font{Size: float64(10 + i%5)},
+			Border: []excelize.Border{excelizestyle.Border(excelizestyle.BorderPositionTop, excelizestyle.BorderStyle(i%5+1), excelizestyle.BorderColorBlack)},
+		}
+	}
+	borderRange := excelizeam.BorderRange{
+		Top:    &excelizeam.BorderItem{Style: excelizestyle.BorderStyleContinuous2, Color: excelizestyle.BorderColorBlack},
+		Bottom: &excelizeam.BorderItem{Style: excelizestyle.BorderStyleContinuous2, Color: excelizestyle.BorderColorBlack},
+		Left:   &excelizeam.BorderItem{Style: excelizestyle.BorderStyleContinuous2, Color: excelizestyle.BorderColorBlack},
+		Right:  &excelizeam.BorderItem{Style: excelizestyle.BorderStyleContinuous2, Color: excelizestyle.BorderColorBlack},
+		Inside: &excelizeam.BorderItem{Style: excelizestyle.BorderStyleDash2, Color: excelizestyle.BorderColorBlack},
+	}
+	tests := map[string]struct {
+		call func(w excelizeam.Excelizeam, i int) error
+	}{
+		"same_cell_async": {
+			call: func(w excelizeam.Excelizeam, i int) error {
+				style := newStyle(i)
+				w.SetStyleCellAsync(1, 1, style, true)
+				w.SetCellValueAsync(1, 1, nil, &style, true)
+				return nil
+			},
+		},
+		"same_cell_sync": {
+			call: func(w excelizeam.Excelizeam, i int) error {
+				style := newStyle(i)
+				if err := w.SetCellValue(1, 1, fmt.Sprintf("test%d", i), &style, true, true); err != nil {
+					return err
+				}
+				return w.SetStyleCell(1, 1, style, true)
+			},
+		},
+		"same_cell_sync_and_async": {
+			call: func(w excelizeam.Excelizeam, i int) error {
+				style := newStyle(i)
+				w.SetStyleCellAsync(1, 1, style, true)
+				if i%10 == 0 {
+					return w.SetCellValue(1, 1, fmt.Sprintf("test%d", i), &style, true, true)
+				}
+				return nil
+			},
+		},
+		"overlapping_ranges_async": {
+			call: func(w excelizeam.Excelizeam, i int) error {
+				w.SetStyleCellRangeAsync(1, 1, 3, 3, newStyle(i), true)
+				w.SetBorderRangeAsync(i%2+1, i%2+1, 3, 3, borderRange, true)
+				return nil
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := excelizeam.New("test")
+			assert.NilError(t, err)
+			assert.NilError(t, w.SetAsyncLimit(goroutines, 1))
+
+			var wg sync.WaitGroup
+			errs := make(chan error, goroutines)
+			for g := 0; g < goroutines; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					for i := 0; i < calls; i++ {
+						if err := tt.call(w, g*calls+i); err != nil {
+							errs <- err
+							return
+						}
+					}
+				}(g)
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				assert.NilError(t, err)
+			}
+			assert.NilError(t, w.Wait())
+			assert.NilError(t, w.Write(io.Discard))
+		})
+	}
+}
+
+func TestExcelizeam_OverridePolicy(t *testing.T) {
+	t.Parallel()
+	fill := excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"FF0000"}}
+	sumFontSize := excelizeam.OverridePolicyFunc(func(existing, style *excelize.Style) *excelize.Style {
+		existing.Font = &excelize.Font{Size: existing.Font.Size + style.Font.Size}
+		return existing
+	})
+	tests := map[string]struct {
+		defaultPolicy *excelizeam.OverridePolicy
+		testFunc      func(w excelizeam.Excelizeam) error
+		wantValue     string
+		wantFontSize  float64
+		wantFill      bool
+		wantBorder    bool
+		wantErr       error
+	}{
+		"Error": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				return w.SetCellValueWithPolicy(1, 1, "new", &excelize.Style{Font: &excelize.Font{Size: 13}}, excelizeam.OverridePolicyError)
+			},
+			wantErr: excelizeam.ErrOverrideCellValue,
+		},
+		"Error-style": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				return w.SetStyleCellWithPolicy(1, 1, excelize.Style{Font: &excelize.Font{Size: 13}}, excelizeam.OverridePolicyError)
+			},
+			wantErr: excelizeam.ErrOverrideCellStyle,
+		},
+		"KeepExisting": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				return w.SetCellValueWithPolicy(1, 1, "new", &excelize.Style{Font: &excelize.Font{Size: 13}}, excelizeam.OverridePolicyKeepExisting)
+			},
+			wantValue:    "old",
+			wantFontSize: 12,
+			wantFill:     true,
+		},
+		"Replace": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				return w.SetCellValueWithPolicy(1, 1, "new", &excelize.Style{Font: &excelize.Font{Size: 13}}, excelizeam.OverridePolicyReplace)
+			},
+			wantValue:    "new",
+			wantFontSize: 13,
+		},
+		"Merge": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				return w.SetCellValueWithPolicy(1, 1, "new", &excelize.Style{Font: &excelize.Font{Size: 13}}, excelizeam.OverridePolicyMerge)
+			},
+			wantValue:    "new",
+			wantFontSize: 13,
+			wantFill:     true,
+		},
+		"Func": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				return w.SetCellValueWithPolicy(1, 1, "new", &excelize.Style{Font: &excelize.Font{Size: 13}}, sumFontSize)
+			},
+			wantValue:    "new",
+			wantFontSize: 25,
+			wantFill:     true,
+		},
+		"Func-async": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				w.SetStyleCellWithPolicyAsync(1, 1, excelize.Style{Font: &excelize.Font{Size: 13}}, sumFontSize)
+				w.SetStyleCellWithPolicyAsync(1, 1, excelize.Style{Font: &excelize.Font{Size: 1}}, sumFontSize)
+				return w.Wait()
+			},
+			wantValue:    "old",
+			wantFontSize: 26,
+			wantFill:     true,
+		},
+		"Func-nil": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				return w.SetStyleCellWithPolicy(1, 1, excelize.Style{Font: &excelize.Font{Size: 13}}, excelizeam.OverridePolicyFunc(nil))
+			},
+			wantValue:    "old",
+			wantFontSize: 12,
+			wantFill:     true,
+		},
+		"default_policy": {
+			defaultPolicy: &excelizeam.OverridePolicyReplace,
+			testFunc: func(w excelizeam.Excelizeam) error {
+				w.SetCellValueWithPolicyAsync(1, 1, "new", &excelize.Style{Font: &excelize.Font{Size: 13}}, excelizeam.OverridePolicy{})
+				return w.Wait()
+			},
+			wantValue:    "new",
+			wantFontSize: 13,
+		},
+		"StyleCellRange-KeepExisting": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				return w.SetStyleCellRangeWithPolicy(1, 1, 2, 2, excelize.Style{Font: &excelize.Font{Size: 13}}, excelizeam.OverridePolicyKeepExisting)
+			},
+			wantValue:    "old",
+			wantFontSize: 12,
+			wantFill:     true,
+		},
+		"BorderRange-Replace": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				w.SetBorderRangeWithPolicyAsync(1, 1, 2, 2, excelizeam.BorderRange{
+					Top: &excelizeam.BorderItem{Style: excelizestyle.BorderStyleContinuous2, Color: excelizestyle.BorderColorBlack},
+				}, excelizeam.OverridePolicyReplace)
+				return w.Wait()
+			},
+			wantValue:  "old",
+			wantBorder: true,
+		},
+		"BorderRange-Merge": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				return w.SetBorderRangeWithPolicy(1, 1, 2, 2, excelizeam.BorderRange{
+					Top: &excelizeam.BorderItem{Style: excelizestyle.BorderStyleContinuous2, Color: excelizestyle.BorderColorBlack},
+				}, excelizeam.OverridePolicyMerge)
+			},
+			wantValue:    "old",
+			wantFontSize: 12,
+			wantFill:     true,
+			wantBorder:   true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := excelizeam.New("test")
+			assert.NilError(t, err)
+			if tt.defaultPolicy != nil {
+				w.SetOverridePolicy(*tt.defaultPolicy)
+			}
+			assert.NilError(t, w.SetCellValue(1, 1, "old", &excelize.Style{Font: &excelize.Font{Size: 12}, Fill: fill}, false, false))
+			err = tt.testFunc(w)
+			if tt.wantErr != nil {
+				assert.Assert(t, errors.Is(err, tt.wantErr))
+				return
+			}
+			assert.NilError(t, err)
+
+			f, err := w.File()
+			assert.NilError(t, err)
+			value, err := f.GetCellValue("test", "A1")
+			assert.NilError(t, err)
+			assert.Equal(t, tt.wantValue, value)
+			styleID, err := f.GetCellStyle("test", "A1")
+			assert.NilError(t, err)
+			style, ok := w.StyleByID(styleID)
+			assert.Assert(t, ok)
+			if tt.wantFontSize > 0 {
+				assert.Equal(t, tt.wantFontSize, style.Font.Size)
+			} else {
+				assert.Assert(t, style.Font == nil)
+			}
+			assert.Equal(t, tt.wantFill, style.Fill.Type != "")
+			_, hasBorder := excelizestyle.FindBorder(style.Border, excelizestyle.BorderPositionTop)
+			assert.Equal(t, tt.wantBorder, hasBorder)
+		})
+	}
+}
+
+func TestNew_Options(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		opts        []excelizeam.Option
+		testFunc    func(w excelizeam.Excelizeam) error
+		openOptions []excelize.Options
+		check       func(t *testing.T, w excelizeam.Excelizeam, f *excelize.File)
+		// wantWriteErrNum number of errors joined into the error returned by Write
+		wantWriteErrNum int
+		wantErr         error
+	}{
+		"no_options": {
+			check: func(t *testing.T, w excelizeam.Excelizeam, f *excelize.File) {
+				value, err := f.GetCellValue("test", "A1")
+				assert.NilError(t, err)
+				assert.Equal(t, "test", value)
+			},
+		},
+		"WithAsyncLimit": {
+			opts: []excelizeam.Option{excelizeam.WithAsyncLimit(2, 3)},
+			testFunc: func(w excelizeam.Excelizeam) error {
+				for rowIdx := 2; rowIdx <= 10; rowIdx++ {
+					w.SetCellValueAsync(1, rowIdx, rowIdx, nil, false)
+				}
+				return w.Wait()
+			},
+			check: func(t *testing.T, w excelizeam.Excelizeam, f *excelize.File) {
+				value, err := f.GetCellValue("test", "A10")
+				assert.NilError(t, err)
+				assert.Equal(t, "10", value)
+			},
+		},
+		"WithAsyncLimit-invalid": {
+			opts:    []excelizeam.Option{excelizeam.WithAsyncLimit(0, 3)},
+			wantErr: excelizeam.ErrInvalidAsyncLimit,
+		},
+		"WithAsyncErrorLimit": {
+			opts: []excelizeam.Option{excelizeam.WithAsyncErrorLimit(0)},
+			testFunc: func(w excelizeam.Excelizeam) error {
+				w.SetCellValueAsync(1, 1, "test2", nil, false)
+				w.SetCellValueAsync(1, 1, "test3", nil, false)
+				return nil
+			},
+			wantWriteErrNum: 2,
+		},
+		"WithAsyncErrorLimit-invalid": {
+			opts:    []excelizeam.Option{excelizeam.WithAsyncErrorLimit(-1)},
+			wantErr: excelizeam.ErrInvalidAsyncLimit,
+		},
+		"WithDefaultBorderStyle": {
+			opts: []excelizeam.Option{excelizeam.WithDefaultBorderStyle(excelizestyle.BorderStyleContinuous1, excelizestyle.BorderColorBlack)},
+			testFunc: func(w excelizeam.Excelizeam) error {
+				return w.SetCellValue(2, 2, "test", nil, false, false)
+			},
+			check: func(t *testing.T, w excelizeam.Excelizeam, f *excelize.File) {
+				styleID, err := f.GetCellStyle("test", "B1")
+				assert.NilError(t, err)
+				style, ok := w.StyleByID(styleID)
+				assert.Assert(t, ok)
+				assert.Equal(t, 4, len(style.Border))
+			},
+		},
+		"WithDefaultBorderColumns": {
+			opts: []excelizeam.Option{
+				excelizeam.WithDefaultBorderStyle(excelizestyle.BorderStyleContinuous1, excelizestyle.BorderColorBlack),
+				excelizeam.WithDefaultBorderColumns(3),
+			},
+			testFunc: func(w excelizeam.Excelizeam) error {
+				return w.SetCellValue(1, 2, "test", nil, false, false)
+			},
+			check: func(t *testing.T, w excelizeam.Excelizeam, f *excelize.File) {
+				styleID, err := f.GetCellStyle("test", "C1")
+				assert.NilError(t, err)
+				style, ok := w.StyleByID(styleID)
+				assert.Assert(t, ok)
+				assert.Equal(t, 4, len(style.Border))
+			},
+		},
+		"WithDefaultBorderColumns-invalid": {
+			opts:    []excelizeam.Option{excelizeam.WithDefaultBorderColumns(-1)},
+			wantErr: excelize.ErrColumnNumber,
+		},
+		"WithOverridePolicy": {
+			opts: []excelizeam.Option{excelizeam.WithOverridePolicy(excelizeam.OverridePolicyReplace)},
+			testFunc: func(w excelizeam.Excelizeam) error {
+				return w.SetCellValueWithPolicy(1, 1, "replaced", nil, excelizeam.OverridePolicy{})
+			},
+			check: func(t *testing.T, w excelizeam.Excelizeam, f *excelize.File) {
+				value, err := f.GetCellValue("test", "A1")
+				assert.NilError(t, err)
+				assert.Equal(t, "replaced", value)
+			},
+		},
+		"WithDate1904": {
+			opts: []excelizeam.Option{excelizeam.WithDate1904()},
+			check: func(t *testing.T, w excelizeam.Excelizeam, f *excelize.File) {
+				props, err := f.GetWorkbookProps()
+				assert.NilError(t, err)
+				assert.Assert(t, props.Date1904 != nil && *props.Date1904)
+			},
+		},
+		"WithExcelizeOptions": {
+			opts:        []excelizeam.Option{excelizeam.WithExcelizeOptions(excelize.Options{Password: "password"})},
+			openOptions: []excelize.Options{{Password: "password"}},
+			check: func(t *testing.T, w excelizeam.Excelizeam, f *excelize.File) {
+				value, err := f.GetCellValue("test", "A1")
+				assert.NilError(t, err)
+				assert.Equal(t, "test", value)
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := excelizeam.New("test", tt.opts...)
+			if tt.wantErr != nil {
+				assert.ErrorContains(t, err, tt.wantErr.Error())
+				return
+			}
+			assert.NilError(t, err)
+			assert.NilError(t, w.SetCellValue(1, 1, "test", nil, false, false))
+			if tt.testFunc != nil {
+				assert.NilError(t, tt.testFunc(w))
+			}
+			var buf bytes.Buffer
+			err = w.Write(&buf)
+			if tt.wantWriteErrNum > 0 {
+				joined, ok := err.(interface{ Unwrap() []error })
+				assert.Assert(t, ok)
+				assert.Equal(t, tt.wantWriteErrNum, len(joined.Unwrap()))
+				return
+			}
+			assert.NilError(t, err)
+			if len(tt.openOptions) > 0 {
+				_, err := excelize.OpenReader(bytes.NewReader(buf.Bytes()))
+				assert.Assert(t, err != nil)
+			}
+			f, err := excelize.OpenReader(&buf, tt.openOptions...)
+			assert.NilError(t, err)
+			if tt.check != nil {
+				tt.check(t, w, f)
+			}
+		})
+	}
+}
+
+func TestNewFromReader(t *testing.T) {
+	t.Parallel()
+	template := func(t *testing.T) []byte {
+		f := excelize.NewFile()
+		assert.NilError(t, f.SetSheetName("Sheet1", "Template"))
+		titleStyleID, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 16}})
+		assert.NilError(t, err)
+		fillStyleID, err := f.NewStyle(&excelize.Style{Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"DDDDDD"}}})
+		assert.NilError(t, err)
+		assert.NilError(t, f.SetCellValue("Template", "A1", "Title"))
+		assert.NilError(t, f.SetCellStyle("Template", "A1", "A1", titleStyleID))
+		assert.NilError(t, f.SetCellValue("Template", "B1", 10))
+		assert.NilError(t, f.SetCellStyle("Template", "C1", "C1", fillStyleID))
+		assert.NilError(t, f.SetCellStyle("Template", "E7", "E7", fillStyleID))
+		assert.NilError(t, f.SetRowStyle("Template", 3, 3, titleStyleID))
+		assert.NilError(t, f.SetRowHeight("Template", 1, 50))
+		orientation := "landscape"
+		assert.NilError(t, f.SetPageLayout("Template", &excelize.PageLayoutOptions{Orientation: &orientation}))
+		_, err = f.NewSheet("Other")
+		assert.NilError(t, err)
+		assert.NilError(t, f.SetCellValue("Other", "A1", "keep"))
+		buf, err := f.WriteToBuffer()
+		assert.NilError(t, err)
+		return buf.Bytes()
+	}
+
+	tests := map[string]struct {
+		sheetName string
+		testFunc  func(t *testing.T, w excelizeam.Excelizeam)
+		check     func(t *testing.T, f *excelize.File)
+		wantErr   error
+	}{
+		"existing_sheet": {
+			sheetName: "Template",
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				styleID, err := w.StyleIDFromCell("Template", "A1")
+				assert.NilError(t, err)
+				style, ok := w.StyleByID(styleID)
+				assert.Assert(t, ok)
+				assert.NilError(t, w.SetCellValue(1, 2, "Subtitle", style, false, false))
+				w.SetCellValueAsync(2, 2, 20, nil, false)
+			},
+			check: func(t *testing.T, f *excelize.File) {
+				rows, err := f.GetRows("Template")
+				assert.NilError(t, err)
+				assert.DeepEqual(t, [][]string{{"Title", "10"}, {"Subtitle", "20"}}, rows)
+				titleStyleID, err := f.GetCellStyle("Template", "A1")
+				assert.NilError(t, err)
+				subtitleStyleID, err := f.GetCellStyle("Template", "A2")
+				assert.NilError(t, err)
+				assert.Equal(t, titleStyleID, subtitleStyleID)
+				cellType, err := f.GetCellType("Template", "B1")
+				assert.NilError(t, err)
+				assert.Assert(t, cellType != excelize.CellTypeSharedString && cellType != excelize.CellTypeInlineString)
+				fillStyleID, err := f.GetCellStyle("Template", "C1")
+				assert.NilError(t, err)
+				fillStyle, err := f.GetStyle(fillStyleID)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, []string{"DDDDDD"}, fillStyle.Fill.Color)
+				// cells with style only outside the dimension written by excelize and the styles of the rows are kept
+				styleID, err := f.GetCellStyle("Template", "E7")
+				assert.NilError(t, err)
+				assert.Equal(t, fillStyleID, styleID)
+				styleID, err = f.GetCellStyle("Template", "F3")
+				assert.NilError(t, err)
+				assert.Equal(t, titleStyleID, styleID)
+				height, err := f.GetRowHeight("Template", 1)
+				assert.NilError(t, err)
+				assert.Equal(t, 50.0, height)
+				layout, err := f.GetPageLayout("Template")
+				assert.NilError(t, err)
+				assert.Equal(t, "landscape", *layout.Orientation)
+			},
+		},
+		"existing_sheet_override_error": {
+			sheetName: "Template",
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				err := w.SetCellValue(1, 1, "override", nil, false, false)
+				assert.Assert(t, errors.Is(err, excelizeam.ErrOverrideCellValue))
+			},
+		},
+		"new_sheet": {
+			sheetName: "Data",
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				assert.NilError(t, w.SetCellValue(1, 1, "data", nil, false, false))
+			},
+			check: func(t *testing.T, f *excelize.File) {
+				assert.DeepEqual(t, []string{"Template", "Other", "Data"}, f.GetSheetList())
+				value, err := f.GetCellValue("Template", "A1")
+				assert.NilError(t, err)
+				assert.Equal(t, "Title", value)
+				value, err = f.GetCellValue("Data", "A1")
+				assert.NilError(t, err)
+				assert.Equal(t, "data", value)
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := excelizeam.NewFromReader(bytes.NewReader(template(t)), tt.sheetName)
+			if tt.wantErr != nil {
+				assert.ErrorContains(t, err, tt.wantErr.Error())
+				return
+			}
+			assert.NilError(t, err)
+			tt.testFunc(t, w)
+			var buf bytes.Buffer
+			assert.NilError(t, w.Write(&buf))
+
+			f, err := excelize.OpenReader(&buf)
+			assert.NilError(t, err)
+			value, err := f.GetCellValue("Other", "A1")
+			assert.NilError(t, err)
+			assert.Equal(t, "keep", value)
+			if tt.check != nil {
+				tt.check(t, f)
+			}
+		})
+	}
+}
+
+func TestNewFromFile(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "template.xlsx")
+	f := excelize.NewFile()
+	assert.NilError(t, f.SetCellValue("Sheet1", "A1", "Title"))
+	assert.NilError(t, f.SaveAs(path))
+
+	w, err := excelizeam.NewFromFile(path, "Sheet1")
+	assert.NilError(t, err)
+	assert.NilError(t, w.SetCellValue(1, 2, "data", nil, false, false))
+	var buf bytes.Buffer
+	assert.NilError(t, w.Write(&buf))
+
+	actual, err := excelize.OpenReader(&buf)
+	assert.NilError(t, err)
+	rows, err := actual.GetRows("Sheet1")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, [][]string{{"Title"}, {"data"}}, rows)
+}
+
+func TestExcelizeam_Import(t *testing.T) {
+	t.Parallel()
+	report := func(t *testing.T) *excelize.File {
+		f := excelize.NewFile()
+		assert.NilError(t, f.SetSheetName("Sheet1", "Report"))
+		styleID, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+		assert.NilError(t, err)
+		assert.NilError(t, f.SetCellValue("Report", "A1", "Report"))
+		assert.NilError(t, f.SetCellStyle("Report", "A1", "A1", styleID))
+		assert.NilError(t, f.MergeCell("Report", "A1", "B1"))
+		assert.NilError(t, f.SetCellValue("Report", "A2", "item"))
+		assert.NilError(t, f.SetCellValue("Report", "B2", 100))
+		assert.NilError(t, f.SetColWidth("Report", "B", "B", 20))
+		assert.NilError(t, f.SetRowHeight("Report", 1, 50))
+		assert.NilError(t, f.SetRowOutlineLevel("Report", 2, 1))
+		assert.NilError(t, f.SetRowVisible("Report", 2, false))
+		fillStyleID, err := f.NewStyle(&excelize.Style{Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"DDDDDD"}}})
+		assert.NilError(t, err)
+		// outside the dimension, which excelize leaves at the cells with value
+		assert.NilError(t, f.SetRowStyle("Report", 4, 4, fillStyleID))
+		assert.NilError(t, f.SetCellStyle("Report", "D5", "D5", styleID))
+		return f
+	}
+
+	tests := map[string]struct {
+		testFunc func(t *testing.T, w excelizeam.Excelizeam, f *excelize.File)
+		check    func(t *testing.T, f *excelize.File)
+	}{
+		"patch": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam, f *excelize.File) {
+				assert.NilError(t, w.Import(f, "Report"))
+				assert.NilError(t, w.SetCellValueWithPolicy(2, 2, 200, nil, excelizeam.OverridePolicyReplace))
+				assert.NilError(t, w.SetBorderRange(1, 2, 2, 3, excelizeam.BorderRange{
+					Top:    &excelizeam.BorderItem{Style: excelizestyle.BorderStyleContinuous1, Color: excelizestyle.BorderColorBlack},
+					Bottom: &excelizeam.BorderItem{Style: excelizestyle.BorderStyleContinuous1, Color: excelizestyle.BorderColorBlack},
+				}, true))
+				w.SetCellValueAsync(1, 3, "added", nil, false)
+			},
+			check: func(t *testing.T, f *excelize.File) {
+				rows, err := f.GetRows("Sheet1")
+				assert.NilError(t, err)
+				assert.DeepEqual(t, [][]string{{"Report"}, {"item", "200"}, {"added"}}, rows)
+				styleID, err := f.GetCellStyle("Sheet1", "A1")
+				assert.NilError(t, err)
+				style, err := f.GetStyle(styleID)
+				assert.NilError(t, err)
+				assert.Assert(t, style.Font != nil && style.Font.Bold)
+				styleID, err = f.GetCellStyle("Sheet1", "B2")
+				assert.NilError(t, err)
+				style, err = f.GetStyle(styleID)
+				assert.NilError(t, err)
+				assert.Equal(t, 1, len(style.Border))
+				mergeCells, err := f.GetMergeCells("Sheet1")
+				assert.NilError(t, err)
+				assert.Equal(t, 1, len(mergeCells))
+				assert.Equal(t, "A1", mergeCells[0].GetStartAxis())
+				assert.Equal(t, "B1", mergeCells[0].GetEndAxis())
+				width, err := f.GetColWidth("Sheet1", "B")
+				assert.NilError(t, err)
+				assert.Equal(t, 20.0, width)
+				width, err = f.GetColWidth("Sheet1", "A")
+				assert.NilError(t, err)
+				assert.Equal(t, 9.140625, width)
+				height, err := f.GetRowHeight("Sheet1", 1)
+				assert.NilError(t, err)
+				assert.Equal(t, 50.0, height)
+				visible, err := f.GetRowVisible("Sheet1", 2)
+				assert.NilError(t, err)
+				assert.Assert(t, !visible)
+				level, err := f.GetRowOutlineLevel("Sheet1", 2)
+				assert.NilError(t, err)
+				assert.Equal(t, uint8(1), level)
+				height, err = f.GetRowHeight("Sheet1", 3)
+				assert.NilError(t, err)
+				assert.Equal(t, 15.0, height)
+				visible, err = f.GetRowVisible("Sheet1", 3)
+				assert.NilError(t, err)
+				assert.Assert(t, visible)
+				styleID, err = f.GetCellStyle("Sheet1", "F4")
+				assert.NilError(t, err)
+				style, err = f.GetStyle(styleID)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, []string{"DDDDDD"}, style.Fill.Color)
+				styleID, err = f.GetCellStyle("Sheet1", "D5")
+				assert.NilError(t, err)
+				style, err = f.GetStyle(styleID)
+				assert.NilError(t, err)
+				assert.Assert(t, style.Font != nil && style.Font.Bold)
+			},
+		},
+		"override_imported_cell": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam, f *excelize.File) {
+				assert.NilError(t, w.Import(f, "Report"))
+				err := w.SetCellValue(1, 2, "override", nil, false, false)
+				assert.Assert(t, errors.Is(err, excelizeam.ErrOverrideCellValue))
+			},
+			check: func(t *testing.T, f *excelize.File) {
+				value, err := f.GetCellValue("Sheet1", "A2")
+				assert.NilError(t, err)
+				assert.Equal(t, "item", value)
+			},
+		},
+		"after_flush": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam, f *excelize.File) {
+				assert.NilError(t, w.SetCellValue(1, 1, "flushed", nil, false, false))
+				assert.NilError(t, w.FlushRows(1))
+				err := w.Import(f, "Report")
+				var flushedErr *excelizeam.RowFlushedError
+				assert.Assert(t, errors.As(err, &flushedErr))
+			},
+		},
+		"sheet_not_exist": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam, f *excelize.File) {
+				err := w.Import(f, "NotExist")
+				assert.Assert(t, errors.As(err, new(excelize.ErrSheetNotExist)))
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := excelizeam.New("Sheet1")
+			assert.NilError(t, err)
+			tt.testFunc(t, w, report(t))
+			var buf bytes.Buffer
+			assert.NilError(t, w.Write(&buf))
+			if tt.check == nil {
+				return
+			}
+			f, err := excelize.OpenReader(&buf)
+			assert.NilError(t, err)
+			tt.check(t, f)
+		})
+	}
+}
+
+func TestExcelizeam_Lifecycle(t *testing.T) {
+	t.Parallel()
+	write := func(w excelizeam.Excelizeam) error {
+		var buf bytes.Buffer
+		return w.Write(&buf)
+	}
+	tests := map[string]struct {
+		testFunc func(w excelizeam.Excelizeam) error
+		wantErr  error
+	}{
+		"SetCellValue-after_Write": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				if err := write(w); err != nil {
+					return err
+				}
+				return w.SetCellValue(1, 3, "after", nil, false, false)
+			},
+			wantErr: excelizeam.ErrAlreadyWritten,
+		},
+		"SetCellValueAsync-after_File": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				if _, err := w.File(); err != nil {
+					return err
+				}
+				w.SetCellValueAsync(1, 3, "after", nil, false)
+				return w.Wait()
+			},
+			wantErr: excelizeam.ErrAlreadyWritten,
+		},
+		"SetColWidth-after_Write": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				if err := write(w); err != nil {
+					return err
+				}
+				return w.SetColWidth(1, 10)
+			},
+			wantErr: excelizeam.ErrAlreadyWritten,
+		},
+		"MergeCell-after_Write": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				if err := write(w); err != nil {
+					return err
+				}
+				return w.MergeCell(1, 3, 2, 3)
+			},
+			wantErr: excelizeam.ErrAlreadyWritten,
+		},
+		"FlushRows-after_Write": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				if err := write(w); err != nil {
+					return err
+				}
+				return w.FlushRows(2)
+			},
+			wantErr: excelizeam.ErrAlreadyWritten,
+		},
+		"Write-after_Close": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				if err := w.Close(); err != nil {
+					return err
+				}
+				return write(w)
+			},
+			wantErr: excelizeam.ErrWorkbookClosed,
+		},
+		"SetCellValue-after_Close": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				if err := w.Close(); err != nil {
+					return err
+				}
+				return w.SetCellValue(1, 3, "after", nil, false, false)
+			},
+			wantErr: excelizeam.ErrWorkbookClosed,
+		},
+		"Close-twice": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				if err := write(w); err != nil {
+					return err
+				}
+				if err := w.Close(); err != nil {
+					return err
+				}
+				return w.Close()
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := excelizeam.New("test")
+			assert.NilError(t, err)
+			assert.NilError(t, w.SetCellValue(1, 1, "value", nil, false, false))
+			err = tt.testFunc(w)
+			if tt.wantErr != nil {
+				assert.Assert(t, errors.Is(err, tt.wantErr), "got %v", err)
+				return
+			}
+			assert.NilError(t, err)
+		})
+	}
+}
+
+func TestExcelizeam_WriteTwice(t *testing.T) {
+	t.Parallel()
+	w, err := excelizeam.New("test")
+	assert.NilError(t, err)
+	for rowIdx := 1; rowIdx <= 3; rowIdx++ {
+		w.SetCellValueAsync(1, rowIdx, rowIdx, nil, false)
+	}
+	_, err = w.File()
+	assert.NilError(t, err)
+	var first, second bytes.Buffer
+	assert.NilError(t, w.Write(&first))
+	assert.NilError(t, w.Write(&second))
+	assert.NilError(t, w.Close())
+
+	for _, buf := range []*bytes.Buffer{&first, &second} {
+		actual, err := excelize.OpenReader(buf)
+		assert.NilError(t, err)
+		rows, err := actual.GetRows("test")
+		assert.NilError(t, err)
+		assert.DeepEqual(t, [][]string{{"1"}, {"2"}, {"3"}}, rows)
+	}
+}
+
+func TestExcelizeam_RowOptions(t *testing.T) {
+	t.Parallel()
+	rowStyle := excelize.Style{Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"EEEEEE"}}}
+	tests := map[string]struct {
+		testFunc func(t *testing.T, w excelizeam.Excelizeam)
+		check    func(t *testing.T, f *excelize.File)
+	}{
+		"sync": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				assert.NilError(t, w.SetCellValue(1, 1, "wrapped", nil, false, false))
+				assert.NilError(t, w.SetRowHeight(1, 40))
+				assert.NilError(t, w.SetCellValue(1, 2, "hidden", nil, false, false))
+				assert.NilError(t, w.SetRowHidden(2, true))
+				assert.NilError(t, w.SetCellValue(1, 3, "detail", nil, false, false))
+				assert.NilError(t, w.SetRowOutlineLevel(3, 1))
+				assert.NilError(t, w.SetRowStyle(4, rowStyle))
+			},
+			check: func(t *testing.T, f *excelize.File) {
+				height, err := f.GetRowHeight("test", 1)
+				assert.NilError(t, err)
+				assert.Equal(t, 40.0, height)
+				visible, err := f.GetRowVisible("test", 2)
+				assert.NilError(t, err)
+				assert.Assert(t, !visible)
+				level, err := f.GetRowOutlineLevel("test", 3)
+				assert.NilError(t, err)
+				assert.Equal(t, uint8(1), level)
+				styleID, err := f.GetCellStyle("test", "B4")
+				assert.NilError(t, err)
+				style, err := f.GetStyle(styleID)
+				assert.NilError(t, err)
+				assert.DeepEqual(t, []string{"EEEEEE"}, style.Fill.Color)
+			},
+		},
+		"async_last_call_wins": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				assert.NilError(t, w.SetAsyncLimit(4, 1))
+				for i := 1; i <= 100; i++ {
+					w.SetRowHeightAsync(1, float64(i))
+					w.SetRowHiddenAsync(2, i%2 == 1)
+					w.SetRowOutlineLevelAsync(3, i%7)
+				}
+				w.SetRowStyleAsync(4, rowStyle)
+				assert.NilError(t, w.Wait())
+			},
+			check: func(t *testing.T, f *excelize.File) {
+				height, err := f.GetRowHeight("test", 1)
+				assert.NilError(t, err)
+				assert.Equal(t, 100.0, height)
+				visible, err := f.GetRowVisible("test", 2)
+				assert.NilError(t, err)
+				assert.Assert(t, visible)
+				level, err := f.GetRowOutlineLevel("test", 3)
+				assert.NilError(t, err)
+				assert.Equal(t, uint8(2), level)
+			},
+		},
+		"sync_after_async": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				w.SetRowHeightAsync(1, 30)
+				assert.NilError(t, w.SetRowHeight(1, 20))
+			},
+			check: func(t *testing.T, f *excelize.File) {
+				height, err := f.GetRowHeight("test", 1)
+				assert.NilError(t, err)
+				assert.Equal(t, 20.0, height)
+			},
+		},
+		"flushed_row_error": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				assert.NilError(t, w.SetCellValue(1, 1, "flushed", nil, false, false))
+				assert.NilError(t, w.FlushRows(1))
+				err := w.SetRowHeight(1, 20)
+				var rowErr *excelizeam.RowError
+				assert.Assert(t, errors.As(err, &rowErr))
+				assert.Equal(t, "SetRowHeight", rowErr.Op)
+				assert.Equal(t, 1, rowErr.Row)
+				assert.Assert(t, errors.Is(err, excelizeam.ErrRowFlushed))
+			},
+		},
+		"height_error": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				err := w.SetRowHeight(1, excelize.MaxRowHeight+1)
+				assert.Assert(t, errors.Is(err, excelize.ErrMaxRowHeight))
+			},
+		},
+		"outline_level_error_async": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				w.SetRowOutlineLevelAsync(1, 8)
+				assert.Assert(t, errors.Is(w.Wait(), excelize.ErrOutlineLevel))
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := excelizeam.New("test")
+			assert.NilError(t, err)
+			tt.testFunc(t, w)
+			if tt.check == nil {
+				return
+			}
+			var buf bytes.Buffer
+			assert.NilError(t, w.Write(&buf))
+			f, err := excelize.OpenReader(&buf)
+			assert.NilError(t, err)
+			tt.check(t, f)
+		})
+	}
+}
+
+func TestExcelizeam_Formula(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		opts         []excelizeam.Option
+		testFunc     func(t *testing.T, w excelizeam.Excelizeam)
+		wantCSV      [][]string
+		wantValues   map[string]string
+		wantFormulas map[string]string
+	}{
+		"sync": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				assert.NilError(t, w.SetCellValue(1, 1, 1, nil, false, false))
+				assert.NilError(t, w.SetCellValue(2, 1, 2, nil, false, false))
+				assert.NilError(t, w.SetCellFormula(3, 1, excelizeam.Formula{Expr: "SUM(A1:B1)", Value: 3}, &excelize.Style{Font: &excelize.Font{Bold: true}}))
+			},
+			wantCSV:      [][]string{{"1", "2", "3"}},
+			wantValues:   map[string]string{"C1": "3"},
+			wantFormulas: map[string]string{"C1": "SUM(A1:B1)"},
+		},
+		"async_without_cached_value": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				w.SetCellValueAsync(1, 1, 1, nil, false)
+				w.SetCellFormulaAsync(1, 2, excelizeam.Formula{Expr: "=A1*2"}, nil)
+				assert.NilError(t, w.Wait())
+			},
+			wantCSV:      [][]string{{"1"}, {""}},
+			wantValues:   map[string]string{"A2": ""},
+			wantFormulas: map[string]string{"A2": "A1*2"},
+		},
+		"csv_expr_option": {
+			opts: []excelizeam.Option{excelizeam.WithCSVFormulaMode(excelizeam.CSVFormulaExpr)},
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				assert.NilError(t, w.SetCellFormula(1, 1, excelizeam.Formula{Expr: "TODAY()", Value: 45000}, nil))
+			},
+			wantCSV:      [][]string{{"=TODAY()"}},
+			wantFormulas: map[string]string{"A1": "TODAY()"},
+		},
+		"csv_expr_sheet": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				w.SetCSVFormulaMode(excelizeam.CSVFormulaExpr)
+				assert.NilError(t, w.SetCellValue(1, 1, excelizeam.Formula{Expr: "=1+1", Value: 2}, nil, false, false))
+			},
+			wantCSV:      [][]string{{"=1+1"}},
+			wantValues:   map[string]string{"A1": "2"},
+			wantFormulas: map[string]string{"A1": "1+1"},
+		},
+		"array_formula": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				assert.NilError(t, w.SetCellValue(1, 1, 1, nil, false, false))
+				assert.NilError(t, w.SetCellFormula(1, 2, excelizeam.Formula{Expr: "A1:A1*2", Value: 2, Array: true}, nil))
+			},
+			wantCSV:      [][]string{{"1"}, {"2"}},
+			wantValues:   map[string]string{"A2": "2"},
+			wantFormulas: map[string]string{"A2": "A1:A1*2"},
+		},
+		"array_formula_ref_error": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				assert.NilError(t, w.SetCellValue(1, 1, 1, nil, false, false))
+				err := w.SetCellFormula(1, 2, excelizeam.Formula{Expr: "A1:A1*2", Array: true, Ref: "A1:A2"}, nil)
+				assert.Assert(t, errors.Is(err, excelizeam.ErrArrayFormulaRef))
+				var cellErr *excelizeam.CellError
+				assert.Assert(t, errors.As(err, &cellErr))
+				assert.Equal(t, "A2", cellErr.CellName)
+			},
+			wantCSV:      [][]string{{"1"}},
+			wantFormulas: map[string]string{"A2": ""},
+		},
+		"override_error": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				assert.NilError(t, w.SetCellValue(1, 1, 1, nil, false, false))
+				err := w.SetCellFormula(1, 1, excelizeam.Formula{Expr: "1+1"}, nil)
+				assert.Assert(t, errors.Is(err, excelizeam.ErrOverrideCellValue))
+			},
+			wantCSV:      [][]string{{"1"}},
+			wantValues:   map[string]string{"A1": "1"},
+			wantFormulas: map[string]string{"A1": ""},
+		},
+		"override_policy": {
+			opts: []excelizeam.Option{excelizeam.WithOverridePolicy(excelizeam.OverridePolicyReplace)},
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				assert.NilError(t, w.SetCellValue(1, 1, 1, nil, false, false))
+				assert.NilError(t, w.SetCellFormula(1, 1, excelizeam.Formula{Expr: "1+1", Value: 2}, nil))
+			},
+			wantCSV:      [][]string{{"2"}},
+			wantValues:   map[string]string{"A1": "2"},
+			wantFormulas: map[string]string{"A1": "1+1"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := excelizeam.New("test", tt.opts...)
+			assert.NilError(t, err)
+			tt.testFunc(t, w)
+			records, err := w.CSVRecords()
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tt.wantCSV, records)
+
+			var buf bytes.Buffer
+			assert.NilError(t, w.Write(&buf))
+			f, err := excelize.OpenReader(&buf)
+			assert.NilError(t, err)
+			for cell, want := range tt.wantValues {
+				value, err := f.GetCellValue("test", cell)
+				assert.NilError(t, err)
+				assert.Equal(t, want, value, cell)
+			}
+			for cell, want := range tt.wantFormulas {
+				formula, err := f.GetCellFormula("test", cell)
+				assert.NilError(t, err)
+				assert.Equal(t, want, formula, cell)
+			}
+		})
+	}
+}
+
+func TestExcelizeam_ArrayFormula(t *testing.T) {
+	t.Parallel()
+	type formula struct {
+		T    string `xml:"t,attr"`
+		Ref  string `xml:"ref,attr"`
+		Expr string `xml:",chardata"`
+	}
+	tests := map[string]struct {
+		testFunc func(t *testing.T, a, b excelizeam.Sheet)
+		// wantFormulas formulas of the sheets a and b keyed by cell name
+		wantFormulas [2]map[string]formula
+	}{
+		"sync": {
+			testFunc: func(t *testing.T, a, b excelizeam.Sheet) {
+				for rowIdx := 1; rowIdx <= 3; rowIdx++ {
+					assert.NilError(t, a.SetCellValue(1, rowIdx, rowIdx, nil, false, false))
+				}
+				assert.NilError(t, a.SetCellFormula(2, 1, excelizeam.Formula{Expr: "A1:A3*2", Value: 2, Array: true, Ref: "B1:B3"}, nil))
+				assert.NilError(t, a.SetCellFormula(3, 1, excelizeam.Formula{Expr: "SUM(A1:A3*2)", Array: true}, nil))
+				assert.NilError(t, a.SetCellFormula(4, 1, excelizeam.Formula{Expr: "A1*2"}, nil))
+				assert.NilError(t, b.SetCellFormula(2, 1, excelizeam.Formula{Expr: "A1:A3*2"}, nil))
+			},
+			wantFormulas: [2]map[string]formula{
+				{
+					"B1": {T: "array", Ref: "B1:B3", Expr: "A1:A3*2"},
+					"C1": {T: "array", Ref: "C1", Expr: "SUM(A1:A3*2)"},
+					"D1": {Expr: "A1*2"},
+				},
+				{
+					"B1": {Expr: "A1:A3*2"},
+				},
+			},
+		},
+		"async_flushed": {
+			testFunc: func(t *testing.T, a, b excelizeam.Sheet) {
+				for rowIdx := 1; rowIdx <= 200; rowIdx++ {
+					a.SetCellFormulaAsync(1, rowIdx, excelizeam.Formula{Expr: "ROW()*{1,2}", Array: true, Ref: fmt.Sprintf("A%d:B%d", rowIdx, rowIdx)}, nil)
+				}
+				assert.NilError(t, a.Wait())
+				assert.NilError(t, a.FlushRows(100))
+				b.SetCellFormulaAsync(1, 1, excelizeam.Formula{Expr: "ROW()*{1,2}"}, nil)
+				assert.NilError(t, b.Wait())
+			},
+			wantFormulas: [2]map[string]formula{
+				{
+					"A1":   {T: "array", Ref: "A1:B1", Expr: "ROW()*{1,2}"},
+					"A100": {T: "array", Ref: "A100:B100", Expr: "ROW()*{1,2}"},
+					"A200": {T: "array", Ref: "A200:B200", Expr: "ROW()*{1,2}"},
+				},
+				{
+					"A1": {Expr: "ROW()*{1,2}"},
+				},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			wb, err := excelizeam.NewWorkbook()
+			assert.NilError(t, err)
+			a, err := wb.AddSheet("a")
+			assert.NilError(t, err)
+			b, err := wb.AddSheet("b")
+			assert.NilError(t, err)
+			tt.testFunc(t, a, b)
+			var buf bytes.Buffer
+			assert.NilError(t, wb.Write(&buf))
+
+			zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			assert.NilError(t, err)
+			for i, path := range []string{"xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+				sheet, err := zr.Open(path)
+				assert.NilError(t, err)
+				var worksheet struct {
+					Rows []struct {
+						Cells []struct {
+							R       string   `xml:"r,attr"`
+							Formula *formula `xml:"f"`
+						} `xml:"c"`
+					} `xml:"sheetData>row"`
+				}
+				err = xml.NewDecoder(sheet).Decode(&worksheet)
+				assert.NilError(t, err)
+				assert.NilError(t, sheet.Close())
+				formulas := make(map[string]formula)
+				for _, row := range worksheet.Rows {
+					for _, c := range row.Cells {
+						if c.Formula != nil {
+							formulas[c.R] = *c.Formula
+						}
+					}
+				}
+				for cell, want := range tt.wantFormulas[i] {
+					assert.DeepEqual(t, want, formulas[cell])
+				}
+			}
+
+			f, err := excelize.OpenReader(bytes.NewReader(buf.Bytes()))
+			assert.NilError(t, err)
+			for i, sheet := range []string{"a", "b"} {
+				for cell, want := range tt.wantFormulas[i] {
+					expr, err := f.GetCellFormula(sheet, cell)
+					assert.NilError(t, err)
+					assert.Equal(t, want.Expr, expr)
+				}
+			}
+		})
+	}
+}
+
+func TestExcelizeam_ImportFormula(t *testing.T) {
+	t.Parallel()
+	src := excelize.NewFile()
+	assert.NilError(t, src.SetCellValue("Sheet1", "A1", 1))
+	assert.NilError(t, src.SetCellFormula("Sheet1", "A2", "A1*2"))
+
+	w, err := excelizeam.New("test", excelizeam.WithCSVFormulaMode(excelizeam.CSVFormulaExpr))
+	assert.NilError(t, err)
+	assert.NilError(t, w.Import(src, "Sheet1"))
+	records, err := w.CSVRecords()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, [][]string{{"1"}, {"=A1*2"}}, records)
+
+	var buf bytes.Buffer
+	assert.NilError(t, w.Write(&buf))
+	f, err := excelize.OpenReader(&buf)
+	assert.NilError(t, err)
+	formula, err := f.GetCellFormula("test", "A2")
+	assert.NilError(t, err)
+	assert.Equal(t, "A1*2", formula)
+}
+
+func TestExcelizeam_Hyperlink(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		opts      []excelizeam.Option
+		testFunc  func(t *testing.T, w excelizeam.Excelizeam)
+		wantLinks map[string]string
+		wantCSV   [][]string
+		check     func(t *testing.T, f *excelize.File)
+	}{
+		"external": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				assert.NilError(t, w.SetCellHyperlink(1, 1, "https://example.com/admin/1", excelizeam.HyperlinkTypeExternal, "admin", "open admin page"))
+			},
+			wantLinks: map[string]string{"A1": "https://example.com/admin/1"},
+			wantCSV:   [][]string{{"admin"}},
+			check: func(t *testing.T, f *excelize.File) {
+				styleID, err := f.GetCellStyle("test", "A1")
+				assert.NilError(t, err)
+				style, err := f.GetStyle(styleID)
+				assert.NilError(t, err)
+				assert.Equal(t, "0563C1", style.Font.Color)
+				assert.Equal(t, "single", style.Font.Underline)
+			},
+		},
+		"location_async": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				for rowIdx := 1; rowIdx <= 10; rowIdx++ {
+					w.SetCellHyperlinkAsync(1, rowIdx, fmt.Sprintf("Detail!A%d", rowIdx), excelizeam.HyperlinkTypeLocation, fmt.Sprintf("row %d", rowIdx), "")
+				}
+				assert.NilError(t, w.Wait())
+			},
+			wantLinks: map[string]string{"A1": "Detail!A1", "A10": "Detail!A10"},
+			wantCSV:   [][]string{{"row 1"}, {"row 2"}, {"row 3"}, {"row 4"}, {"row 5"}, {"row 6"}, {"row 7"}, {"row 8"}, {"row 9"}, {"row 10"}},
+		},
+		"styled_cell": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				assert.NilError(t, w.SetCellValue(1, 1, nil, &excelize.Style{
+					Border: excelizestyle.BorderAround(excelizestyle.BorderStyleContinuous1, excelizestyle.BorderColorBlack),
+				}, false, false))
+				assert.NilError(t, w.SetCellHyperlink(1, 1, "https://example.com", excelizeam.HyperlinkTypeExternal, "link", ""))
+			},
+			wantLinks: map[string]string{"A1": "https://example.com"},
+			wantCSV:   [][]string{{"link"}},
+			check: func(t *testing.T, f *excelize.File) {
+				styleID, err := f.GetCellStyle("test", "A1")
+				assert.NilError(t, err)
+				style, err := f.GetStyle(styleID)
+				assert.NilError(t, err)
+				assert.Equal(t, 4, len(style.Border))
+				assert.Equal(t, "single", style.Font.Underline)
+			},
+		},
+		"without_style": {
+			opts: []excelizeam.Option{excelizeam.WithHyperlinkStyle(nil)},
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				assert.NilError(t, w.SetCellHyperlink(1, 1, "Sheet2!A1", excelizeam.HyperlinkTypeLocation, "", ""))
+			},
+			wantLinks: map[string]string{"A1": "Sheet2!A1"},
+			wantCSV:   [][]string{{""}},
+			check: func(t *testing.T, f *excelize.File) {
+				styleID, err := f.GetCellStyle("test", "A1")
+				assert.NilError(t, err)
+				assert.Equal(t, 0, styleID)
+			},
+		},
+		"invalid_type": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				err := w.SetCellHyperlink(1, 1, "https://example.com", "None", "", "")
+				assert.Assert(t, errors.Is(err, excelizeam.ErrInvalidHyperlinkType))
+			},
+			wantCSV: [][]string{},
+		},
+		"override_error": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				assert.NilError(t, w.SetCellValue(1, 1, "value", nil, false, false))
+				err := w.SetCellHyperlink(1, 1, "https://example.com", excelizeam.HyperlinkTypeExternal, "link", "")
+				assert.Assert(t, errors.Is(err, excelizeam.ErrOverrideCellValue))
+			},
+			wantLinks: map[string]string{"A1": ""},
+			wantCSV:   [][]string{{"value"}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := excelizeam.New("test", tt.opts...)
+			assert.NilError(t, err)
+			tt.testFunc(t, w)
+			records, err := w.CSVRecords()
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tt.wantCSV, records)
+
+			var buf bytes.Buffer
+			assert.NilError(t, w.Write(&buf))
+			f, err := excelize.OpenReader(&buf)
+			assert.NilError(t, err)
+			for cell, want := range tt.wantLinks {
+				ok, target, err := f.GetCellHyperLink("test", cell)
+				assert.NilError(t, err)
+				assert.Equal(t, want != "", ok, cell)
+				assert.Equal(t, want, target, cell)
+			}
+			if tt.check != nil {
+				tt.check(t, f)
+			}
+		})
+	}
+}
+
+func TestExcelizeam_RichText(t *testing.T) {
+	t.Parallel()
+	runs := func() []excelize.RichTextRun {
+		return []excelize.RichTextRun{
+			{Text: "Status: ", Font: &excelize.Font{Bold: true}},
+			{Text: "failed", Font: &excelize.Font{Color: "FF0000"}},
+			{Text: " at step 3"},
+		}
+	}
+	tests := map[string]struct {
+		testFunc func(t *testing.T, w excelizeam.Excelizeam)
+		wantCSV  [][]string
+		wantRuns map[string][]excelize.RichTextRun
+	}{
+		"sync": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				assert.NilError(t, w.SetCellRichText(1, 1, runs(), &excelize.Style{Alignment: &excelize.Alignment{WrapText: true}}))
+			},
+			wantCSV:  [][]string{{"Status: failed at step 3"}},
+			wantRuns: map[string][]excelize.RichTextRun{"A1": runs()},
+		},
+		"async": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				r := runs()
+				w.SetCellRichTextAsync(2, 2, r, nil)
+				// changes made after the call are not stored
+				r[1].Text = "passed"
+				r[1].Font.Color = "00FF00"
+				assert.NilError(t, w.Wait())
+			},
+			wantCSV:  [][]string{{"", ""}, {"", "Status: failed at step 3"}},
+			wantRuns: map[string][]excelize.RichTextRun{"B2": runs()},
+		},
+		"override_error": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) {
+				assert.NilError(t, w.SetCellValue(1, 1, "value", nil, false, false))
+				err := w.SetCellRichText(1, 1, runs(), nil)
+				assert.Assert(t, errors.Is(err, excelizeam.ErrOverrideCellValue))
+			},
+			wantCSV: [][]string{{"value"}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := excelizeam.New("test")
+			assert.NilError(t, err)
+			tt.testFunc(t, w)
+			records, err := w.CSVRecords()
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tt.wantCSV, records)
+
+			var buf bytes.Buffer
+			assert.NilError(t, w.Write(&buf))
+			f, err := excelize.OpenReader(&buf)
+			assert.NilError(t, err)
+			for cell, want := range tt.wantRuns {
+				actual, err := f.GetCellRichText("test", cell)
+				assert.NilError(t, err)
+				assert.Equal(t, len(want), len(actual), cell)
+				for i := range want {
+					assert.Equal(t, want[i].Text, actual[i].Text)
+					if want[i].Font != nil {
+						assert.Equal(t, want[i].Font.Bold, actual[i].Font.Bold)
+						assert.Equal(t, want[i].Font.Color, actual[i].Font.Color)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestExcelizeam_ImportRichText(t *testing.T) {
+	t.Parallel()
+	src := excelize.NewFile()
+	assert.NilError(t, src.SetCellRichText("Sheet1", "A1", []excelize.RichTextRun{
+		{Text: "bold", Font: &excelize.Font{Bold: true}},
+		{Text: " plain"},
+	}))
+
+	w, err := excelizeam.New("test")
+	assert.NilError(t, err)
+	assert.NilError(t, w.Import(src, "Sheet1"))
+	var buf bytes.Buffer
+	assert.NilError(t, w.Write(&buf))
+	f, err := excelize.OpenReader(&buf)
+	assert.NilError(t, err)
+	runs, err := f.GetCellRichText("test", "A1")
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(runs))
+	assert.Assert(t, runs[0].Font != nil && runs[0].Font.Bold)
+	assert.Equal(t, " plain", runs[1].Text)
+}
+
+func TestExcelizeam_AddTable(t *testing.T) {
+	t.Parallel()
+	setRows := func(t *testing.T, w excelizeam.Excelizeam, headers ...interface{}) {
+		for colIdx, header := range headers {
+			assert.NilError(t, w.SetCellValue(colIdx+1, 1, header, nil, false, false))
+			assert.NilError(t, w.SetCellValue(colIdx+1, 2, colIdx, nil, false, false))
+		}
+	}
+	tests := map[string]struct {
+		testFunc  func(t *testing.T, w excelizeam.Excelizeam) error
+		wantTable *excelize.Table
+		wantErr   error
+	}{
+		"default_style": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) error {
+				setRows(t, w, "ID", "Name")
+				return w.AddTable(1, 1, 2, 2, nil)
+			},
+			wantTable: &excelize.Table{Range: "A1:B2", Name: "Table1", StyleName: excelizeam.DefaultTableStyleName},
+		},
+		"options": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) error {
+				setRows(t, w, "ID", "Name", "Price")
+				for rowIdx := 3; rowIdx <= 10; rowIdx++ {
+					w.SetCellValueAsync(1, rowIdx, rowIdx, nil, false)
+				}
+				return w.AddTable(1, 1, 3, 10, &excelize.Table{Name: "Items", StyleName: "TableStyleLight9", ShowFirstColumn: true})
+			},
+			wantTable: &excelize.Table{Range: "A1:C10", Name: "Items", StyleName: "TableStyleLight9", ShowFirstColumn: true},
+		},
+		"header_not_string": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) error {
+				setRows(t, w, "ID", 2)
+				return w.AddTable(1, 1, 2, 2, nil)
+			},
+			wantErr: excelizeam.ErrTableHeader,
+		},
+		"header_empty": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) error {
+				setRows(t, w, "ID")
+				return w.AddTable(1, 1, 2, 2, nil)
+			},
+			wantErr: excelizeam.ErrTableHeader,
+		},
+		"header_duplicate": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) error {
+				setRows(t, w, "Name", "name")
+				return w.AddTable(1, 1, 2, 2, nil)
+			},
+			wantErr: excelizeam.ErrTableHeader,
+		},
+		"header_only": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) error {
+				setRows(t, w, "ID", "Name")
+				return w.AddTable(1, 1, 2, 1, nil)
+			},
+			wantErr: excelizeam.ErrTableRange,
+		},
+		"header_flushed": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) error {
+				setRows(t, w, "ID", "Name")
+				assert.NilError(t, w.FlushRows(1))
+				return w.AddTable(1, 1, 2, 2, nil)
+			},
+			wantErr: excelizeam.ErrRowFlushed,
+		},
+		"second_table": {
+			testFunc: func(t *testing.T, w excelizeam.Excelizeam) error {
+				setRows(t, w, "ID", "Name")
+				assert.NilError(t, w.AddTable(1, 1, 1, 2, nil))
+				return w.AddTable(2, 1, 2, 2, nil)
+			},
+			wantErr: excelizeam.ErrTableAlreadyExists,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := excelizeam.New("test")
+			assert.NilError(t, err)
+			err = tt.testFunc(t, w)
+			if tt.wantErr != nil {
+				assert.Assert(t, errors.Is(err, tt.wantErr), "got %v", err)
+				return
+			}
+			assert.NilError(t, err)
+
+			var buf bytes.Buffer
+			assert.NilError(t, w.Write(&buf))
+			f, err := excelize.OpenReader(&buf)
+			assert.NilError(t, err)
+			tables, err := f.GetTables("test")
+			assert.NilError(t, err)
+			assert.Equal(t, 1, len(tables))
+			assert.Equal(t, tt.wantTable.Range, tables[0].Range)
+			assert.Equal(t, tt.wantTable.Name, tables[0].Name)
+			assert.Equal(t, tt.wantTable.StyleName, tables[0].StyleName)
+			assert.Equal(t, tt.wantTable.ShowFirstColumn, tables[0].ShowFirstColumn)
+		})
+	}
+}
+
+func TestExcelizeam_Panes(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		// withoutHeader leaves the first row empty
+		withoutHeader bool
+		testFunc      func(w excelizeam.Excelizeam) error
+		wantPanes     excelize.Panes
+		wantErr       error
+	}{
+		"SetFreezePanes-header_row": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				return w.SetFreezePanes(0, 1)
+			},
+			wantPanes: excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"},
+		},
+		"SetFreezePanes-header_row_and_key_columns": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				return w.SetFreezePanes(2, 1)
+			},
+			wantPanes: excelize.Panes{Freeze: true, XSplit: 2, YSplit: 1, TopLeftCell: "C2", ActivePane: "bottomRight"},
+		},
+		"SetFreezePanes-after_rows": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				for rowIdx := 2; rowIdx <= 100; rowIdx++ {
+					w.SetCellValueAsync(1, rowIdx, rowIdx, nil, false)
+				}
+				return w.SetFreezePanes(1, 0)
+			},
+			wantPanes: excelize.Panes{Freeze: true, XSplit: 1, TopLeftCell: "B1", ActivePane: "topRight"},
+		},
+		"SetFreezePanes-remove": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				if err := w.SetFreezePanes(0, 1); err != nil {
+					return err
+				}
+				return w.SetFreezePanes(0, 0)
+			},
+		},
+		"SetSplitPanes": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				return w.SetSplitPanes(3000, 1800)
+			},
+			// excelize reads a pane without state, which is a split pane, as not split
+			wantPanes: excelize.Panes{XSplit: 3000, YSplit: 1800, TopLeftCell: "A1", ActivePane: "bottomRight"},
+		},
+		"SetFreezePanes-flushed_error": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				if err := w.FlushRows(1); err != nil {
+					return err
+				}
+				return w.SetFreezePanes(0, 1)
+			},
+			wantErr: excelizeam.ErrRowFlushed,
+		},
+		"SetFreezePanes-flushed_without_rows": {
+			withoutHeader: true,
+			testFunc: func(w excelizeam.Excelizeam) error {
+				if err := w.FlushRows(1); err != nil {
+					return err
+				}
+				if err := w.SetFreezePanes(0, 1); err != nil {
+					return err
+				}
+				return w.SetCellValue(1, 2, "value", nil, false, false)
+			},
+			wantPanes: excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"},
+		},
+		"SetFreezePanes-invalid": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				return w.SetFreezePanes(-1, 1)
+			},
+			wantErr: excelize.ErrParameterInvalid,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := excelizeam.New("test")
+			assert.NilError(t, err)
+			if !tt.withoutHeader {
+				assert.NilError(t, w.SetCellValue(1, 1, "header", nil, false, false))
+			}
+			err = tt.testFunc(w)
+			if tt.wantErr != nil {
+				assert.Assert(t, errors.Is(err, tt.wantErr), "got %v", err)
+				return
+			}
+			assert.NilError(t, err)
+
+			var buf bytes.Buffer
+			assert.NilError(t, w.Write(&buf))
+			f, err := excelize.OpenReader(&buf)
+			assert.NilError(t, err)
+			panes, err := f.GetPanes("test")
+			assert.NilError(t, err)
+			panes.Selection = nil
+			assert.DeepEqual(t, tt.wantPanes, panes)
+		})
+	}
+}
+
+func TestExcelizeam_SetConditionalFormat(t *testing.T) {
+	t.Parallel()
+	negative := excelize.Style{
+		Font: &excelize.Font{Color: "9C0006"},
+		Fill: excelizestyle.Fill(excelizestyle.FillPatternSolid, "FFC7CE"),
+	}
+	tests := map[string]struct {
+		rangeRef  string
+		rules     []excelizeam.ConditionalFormatRule
+		wantTypes []string
+		wantErr   bool
+	}{
+		"CellRule": {
+			rangeRef:  "B2:B11",
+			rules:     []excelizeam.ConditionalFormatRule{excelizeam.CellRule(excelizeam.ConditionalCriteriaLessThan, "0", negative)},
+			wantTypes: []string{"cell"},
+		},
+		"FormulaRule-StopIfTrue": {
+			rangeRef: "A2:C11",
+			rules: []excelizeam.ConditionalFormatRule{
+				excelizeam.FormulaRule("$B2<0", negative).StopIfTrue(),
+				excelizeam.CellBetweenRule("0", "5", excelize.Style{Font: &excelize.Font{Bold: true}}),
+			},
+			wantTypes: []string{"formula", "cell"},
+		},
+		"scales": {
+			rangeRef: "B2:B11",
+			rules: []excelizeam.ConditionalFormatRule{
+				excelizeam.ColorScaleRule("F8696B", "63BE7B"),
+				excelizeam.ThreeColorScaleRule("F8696B", "FFEB84", "63BE7B"),
+				excelizeam.DataBarRule("638EC6"),
+				excelizeam.IconSetRule(excelizeam.IconStyle3Arrows),
+			},
+			wantTypes: []string{"2_color_scale", "3_color_scale", "data_bar", "icon_set"},
+		},
+		"invalid_range": {
+			rangeRef: "B2:",
+			rules:    []excelizeam.ConditionalFormatRule{excelizeam.DataBarRule("638EC6")},
+			wantErr:  true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := excelizeam.New("test")
+			assert.NilError(t, err)
+			for rowIdx := 2; rowIdx <= 11; rowIdx++ {
+				w.SetCellValueAsync(1, rowIdx, fmt.Sprintf("item%d", rowIdx), nil, false)
+				w.SetCellValueAsync(2, rowIdx, rowIdx-6, nil, false)
+			}
+			err = w.SetConditionalFormat(tt.rangeRef, tt.rules)
+			if tt.wantErr {
+				assert.Assert(t, err != nil)
+				return
+			}
+			assert.NilError(t, err)
+			// the differential format is shared by the rules with the same style
+			assert.NilError(t, w.SetConditionalFormat("D2:D11", []excelizeam.ConditionalFormatRule{excelizeam.CellRule(excelizeam.ConditionalCriteriaLessThan, "0", negative)}))
+
+			var buf bytes.Buffer
+			assert.NilError(t, w.Write(&buf))
+			f, err := excelize.OpenReader(&buf)
+			assert.NilError(t, err)
+			rows, err := f.GetRows("test")
+			assert.NilError(t, err)
+			assert.Equal(t, 11, len(rows))
+			formats, err := f.GetConditionalFormats("test")
+			assert.NilError(t, err)
+			rules := formats[tt.rangeRef]
+			assert.Equal(t, len(tt.wantTypes), len(rules))
+			for i, rule := range rules {
+				assert.Equal(t, tt.wantTypes[i], rule.Type)
+			}
+			for _, rule := range append(rules, formats["D2:D11"]...) {
+				if rule.Format == nil {
+					continue
+				}
+				style, err := f.GetConditionalStyle(*rule.Format)
+				assert.NilError(t, err)
+				assert.Assert(t, style.Font != nil)
+			}
+			negativeRules := formats["D2:D11"]
+			assert.Equal(t, 1, len(negativeRules))
+			if name == "CellRule" {
+				assert.Equal(t, *rules[0].Format, *negativeRules[0].Format)
+				style, err := f.GetConditionalStyle(*rules[0].Format)
+				assert.NilError(t, err)
+				assert.Equal(t, "9C0006", style.Font.Color)
+			}
+		})
+	}
+}
+
+func TestExcelizeam_AddDataValidation(t *testing.T) {
+	t.Parallel()
+	longList := make([]string, 100)
+	for i := range longList {
+		longList[i] = fmt.Sprintf("option%03d", i+1)
+	}
+	tests := map[string]struct {
+		rule        excelizeam.DataValidationRule
+		wantType    string
+		wantFormula string
+		wantLookup  []string
+		wantErr     bool
+	}{
+		"ListRule": {
+			rule:        excelizeam.ListRule("yes", "no").WithInput("Answer", "Choose yes or no"),
+			wantType:    "list",
+			wantFormula: `"yes,no"`,
+		},
+		"ListRule-long": {
+			rule:        excelizeam.ListRule(longList...),
+			wantType:    "list",
+			wantFormula: "'" + excelizeam.DataValidationListSheetName + "'!$A$1:$A$100",
+			wantLookup:  longList,
+		},
+		"ListRule-comma": {
+			rule:        excelizeam.ListRule("1,000", "2,000"),
+			wantType:    "list",
+			wantFormula: "'" + excelizeam.DataValidationListSheetName + "'!$A$1:$A$2",
+			wantLookup:  []string{"1,000", "2,000"},
+		},
+		"ListRule-formula_like": {
+			rule:        excelizeam.ListRule("=SUM(A1:A2)", "=B2"),
+			wantType:    "list",
+			wantFormula: "'" + excelizeam.DataValidationListSheetName + "'!$A$1:$A$2",
+			wantLookup:  []string{"=SUM(A1:A2)", "=B2"},
+		},
+		"WholeNumberRule": {
+			rule:        excelizeam.WholeNumberRule(1, 10).WithError(excelize.DataValidationErrorStyleStop, "Invalid", "Enter 1 to 10"),
+			wantType:    "whole",
+			wantFormula: "1",
+		},
+		"DecimalRule": {
+			rule:        excelizeam.DecimalRule(0.5, 1.5),
+			wantType:    "decimal",
+			wantFormula: "0.5",
+		},
+		"DateRule": {
+			rule:        excelizeam.DateRule(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)),
+			wantType:    "date",
+			wantFormula: "DATE(2024,1,1)",
+		},
+		"TextLengthRule": {
+			rule:        excelizeam.TextLengthRule(0, 20),
+			wantType:    "textLength",
+			wantFormula: "0",
+		},
+		"CustomRule": {
+			rule:        excelizeam.CustomRule("=ISNUMBER(B2)"),
+			wantType:    "custom",
+			wantFormula: "ISNUMBER(B2)",
+		},
+		"empty_rule": {
+			rule:    excelizeam.DataValidationRule{},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := excelizeam.New("test")
+			assert.NilError(t, err)
+			assert.NilError(t, w.SetCellValue(1, 1, "header", nil, false, false))
+			err = w.AddDataValidation(2, 2, 2, 11, tt.rule)
+			if tt.wantErr {
+				assert.Assert(t, err != nil)
+				return
+			}
+			assert.NilError(t, err)
+
+			var buf bytes.Buffer
+			assert.NilError(t, w.Write(&buf))
+			f, err := excelize.OpenReader(&buf)
+			assert.NilError(t, err)
+			dvs, err := f.GetDataValidations("test")
+			assert.NilError(t, err)
+			assert.Equal(t, 1, len(dvs))
+			assert.Equal(t, "B2:B11", dvs[0].Sqref)
+			assert.Equal(t, tt.wantType, dvs[0].Type)
+			assert.Equal(t, tt.wantFormula, dvs[0].Formula1)
+
+			if tt.wantLookup == nil {
+				idx, err := f.GetSheetIndex(excelizeam.DataValidationListSheetName)
+				assert.NilError(t, err)
+				assert.Equal(t, -1, idx)
+				return
+			}
+			visible, err := f.GetSheetVisible(excelizeam.DataValidationListSheetName)
+			assert.NilError(t, err)
+			assert.Assert(t, !visible)
+			cols, err := f.GetCols(excelizeam.DataValidationListSheetName)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, [][]string{tt.wantLookup}, cols)
+			// the values are literals even when they look like formulas
+			for rowIdx := range tt.wantLookup {
+				formula, err := f.GetCellFormula(excelizeam.DataValidationListSheetName, fmt.Sprintf("A%d", rowIdx+1))
+				assert.NilError(t, err)
+				assert.Equal(t, "", formula)
+			}
+		})
+	}
+}
+
+func TestExcelizeam_CellError(t *testing.T) {
+	t.Parallel()
+	style := excelize.Style{Font: &excelize.Font{Bold: true}}
+	tests := map[string]struct {
+		testFunc     func(w excelizeam.Excelizeam) error
+		wantOp       string
+		wantCol      int
+		wantRow      int
+		wantCellName string
+		wantErr      error
+	}{
+		"SetCellValue": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				if err := w.SetCellValue(2, 3, "test", nil, false, false); err != nil {
+					return err
+				}
+				return w.SetCellValue(2, 3, "test", nil, false, false)
+			},
+			wantOp:       "SetCellValue",
+			wantCol:      2,
+			wantRow:      3,
+			wantCellName: "B3",
+			wantErr:      excelizeam.ErrOverrideCellValue,
+		},
+		"SetStyleCellAsync": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				w.SetStyleCellAsync(4, 5, style, false)
+				if err := w.Wait(); err != nil {
+					return err
+				}
+				w.SetStyleCellAsync(4, 5, style, false)
+				return w.Wait()
+			},
+			wantOp:       "SetStyleCellAsync",
+			wantCol:      4,
+			wantRow:      5,
+			wantCellName: "D5",
+			wantErr:      excelizeam.ErrOverrideCellStyle,
+		},
+		"SetStyleCellRange": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				if err := w.SetStyleCell(2, 2, style, false); err != nil {
+					return err
+				}
+				return w.SetStyleCellRange(1, 1, 3, 3, style, false)
+			},
+			wantOp:       "SetStyleCellRange",
+			wantCol:      2,
+			wantRow:      2,
+			wantCellName: "B2",
+			wantErr:      excelizeam.ErrOverrideCellStyle,
+		},
+		"SetBorderRangeAsync": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				if err := w.SetStyleCell(3, 1, style, false); err != nil {
+					return err
+				}
+				w.SetBorderRangeAsync(1, 1, 3, 2, excelizeam.BorderRange{
+					Top: &excelizeam.BorderItem{Style: excelizestyle.BorderStyleContinuous2, Color: excelizestyle.BorderColorBlack},
+				}, false)
+				return w.Wait()
+			},
+			wantOp:       "SetBorderRangeAsync",
+			wantCol:      3,
+			wantRow:      1,
+			wantCellName: "C1",
+			wantErr:      excelizeam.ErrOverrideCellStyle,
+		},
+		"flushed_row": {
+			testFunc: func(w excelizeam.Excelizeam) error {
+				if err := w.SetCellValue(1, 1, "test", nil, false, false); err != nil {
+					return err
+				}
+				if err := w.FlushRows(1); err != nil {
+					return err
+				}
+				return w.SetCellValue(1, 1, "test", nil, true, true)
+			},
+			wantOp:       "SetCellValue",
+			wantCol:      1,
+			wantRow:      1,
+			wantCellName: "A1",
+			wantErr:      excelizeam.ErrRowFlushed,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w, err := excelizeam.New("test")
+			assert.NilError(t, err)
+			err = tt.testFunc(w)
+			assert.Assert(t, errors.Is(err, tt.wantErr))
+			var cellErr *excelizeam.CellError
+			assert.Assert(t, errors.As(err, &cellErr))
+			assert.Equal(t, tt.wantOp, cellErr.Op)
+			assert.Equal(t, tt.wantCol, cellErr.Col)
+			assert.Equal(t, tt.wantRow, cellErr.Row)
+			assert.Equal(t, tt.wantCellName, cellErr.CellName)
+		})
+	}
+}
+
+func BenchmarkExcelizeam(b *testing.B) {
+	benchmarks := []struct {
+		name      string
+		benchFunc func(w io.Writer, rows, cols int) error
+		rows      int
+		cols      int
+	}{
+		{name: "Excelize", benchFunc: benchExcelize, rows: 1000, cols: 10},
+		{name: "Excelize Async", benchFunc: benchExcelizeAsync, rows: 1000, cols: 10},
+		{name: "Excelize StreamWriter", benchFunc: benchStream, rows: 1000, cols: 10},
+		{name: "Excelizeam Sync", benchFunc: benchExcelizeam, rows: 1000, cols: 10},
+		{name: "Excelizeam Async", benchFunc: benchExcelizeamAsync, rows: 1000, cols: 10},
+		{name: "Excelize StreamWriter 500k cells", benchFunc: benchStream, rows: 50000, cols: 10},
+		{name: "Excelizeam Sync 500k cells", benchFunc: benchExcelizeam, rows: 50000, cols: 10},
+		{name: "Excelizeam Async 500k cells", benchFunc: benchExcelizeamAsync, rows: 50000, cols: 10},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			var buf bytes.Buffer
+			defer buf.Reset()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				buf.Reset()
+				if err := bm.benchFunc(&buf, bm.rows, bm.cols); err != nil {
+					b.Error(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkExcelizeam_OverrideStyle(b *testing.B) {
+	benchmarks := []struct {
+		name      string
+		benchFunc func(w io.Writer, rows, cols int) error
+		rows      int
+		cols      int
+	}{
+		{name: "Excelizeam Sync", benchFunc: benchExcelizeamOverride, rows: 200, cols: 10},
+		{name: "Excelizeam Async", benchFunc: benchExcelizeamOverrideAsync, rows: 200, cols: 10},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			var buf bytes.Buffer
+			defer buf.Reset()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				buf.Reset()
+				if err := bm.benchFunc(&buf, bm.rows, bm.cols); err != nil {
+					b.Error(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkExcelizeam_AsyncCalls(b *testing.B) {
+	for _, calls := range []int{10000, 100000, 1000000} {
+		b.Run(fmt.Sprintf("%d calls", calls), func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+			var peakGoroutines int
+			var peakHeapInuse uint64
+			for i := 0; i < b.N; i++ {
+				e, err := excelizeam.New("test")
+				if err != nil {
+					b.Fatal(err)
+				}
+				style := excelize.Style{Font: &excelize.Font{Size: 12, Bold: true}}
+				for call := 0; call < calls; call++ {
+					// overrides the same 100 cells so that only the async machinery grows with the number of calls
+					e.SetStyleCellAsync(call%10+1, call/10%10+1, style, true)
+					if call%10000 == 0 {
+						if n := runtime.NumGoroutine(); n > peakGoroutines {
+							peakGoroutines = n
+						}
+						var m runtime.MemStats
+						runtime.ReadMemStats(&m)
+						if m.HeapInuse > peakHeapInuse {
+							peakHeapInuse = m.HeapInuse
+						}
+					}
+				}
+				if err := e.Wait(); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(peakGoroutines), "peak-goroutines")
+			b.ReportMetric(float64(peakHeapInuse)/1024/1024, "peak-heap-MB")
+		})
+	}
+}
+
+func benchExcelize(w io.Writer, rows, cols int) error {
+	f := excelize.NewFile()
+	f.SetSheetName("Sheet1", "test")
+
+	for rowIdx := 1; rowIdx <= rows; rowIdx++ {
+		for colIdx := 1; colIdx <= cols; colIdx++ {
+			cell, err := excelize.CoordinatesToCellName(colIdx, rowIdx)
+			if err != nil {
+				return err
+			}
+			if err := f.SetCellValue("test", cell, fmt.Sprintf("test%d-%d", rowIdx, colIdx)); err != nil {
+				return err
+			}
+			styleID, err := f.NewStyle(&excelize.Style{
+				Border:    excelizestyle.BorderAround(excelizestyle.BorderStyleContinuous2, excelizestyle.BorderColorBlack),
+				Font:      &excelize.Font{Size: 12, Bold: true},
+				Alignment: excelizestyle.Alignment(excelizestyle.AlignmentHorizontalCenter, excelizestyle.AlignmentVerticalCenter, true),
+			})
+			if err != nil {
+				return err
+			}
+			if err := f.SetCellStyle("test", cell, cell, styleID); err != nil {
+				return err
+			}
+		}
+	}
+	return f.Write(w)
+}
+
+func benchExcelizeAsync(w io.Writer, rows, cols int) error {
+	f := excelize.NewFile()
+	f.SetSheetName("Sheet1", "test")
+
+	var eg errgroup.Group
+
+	for rowIdx := 1; rowIdx <= rows; rowIdx++ {
+		rowIdx := rowIdx
+		for colIdx := 1; colIdx <= cols; colIdx++ {
+			colIdx := colIdx
+			eg.Go(func() error {
+				cell, err := excelize.CoordinatesToCellName(colIdx, rowIdx)
+				if err != nil {
+					return err
+				}
+				if err := f.SetCellValue("test", cell, fmt.Sprintf("test%d-%d", rowIdx, colIdx)); err != nil {
+					return err
+				}
+				styleID, err := f.NewStyle(&excelize.Style{
+					Border:    excelizestyle.BorderAround(excelizestyle.BorderStyleContinuous2, excelizestyle.BorderColorBlack),
+					Font:      &excelize.Font{Size: 12, Bold: true},
+					Alignment: excelizestyle.Alignment(excelizestyle.AlignmentHorizontalCenter, excelizestyle.AlignmentVerticalCenter, true),
+				})
+				if err != nil {
+					return err
+				}
+				if err := f.SetCellStyle("test", cell, cell, styleID); err != nil {
+					return err
+				}
+				return nil
+			})
+		}
+	}
+
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+	return f.Write(w)
+}
+
+func benchStream(w io.Writer, rows, cols int) error {
+	f := excelize.NewFile()
+	f.SetSheetName("Sheet1", "test")
+	sw, err := f.NewStreamWriter("test")
+	if err != nil {
+		return err
+	}
+
+	for rowIdx := 1; rowIdx <= rows; rowIdx++ {
+		values := make([]interface{}, cols)
+		for colIdx := 1; colIdx <= cols; colIdx++ {
+			styleID, err := f.NewStyle(&excelize.Style{
+				Border:    excelizestyle.BorderAround(excelizestyle.BorderStyleContinuous2, excelizestyle.BorderColorBlack),
+				Font:      &excelize.Font{Size: 12, Bold: true},
+				Alignment: excelizestyle.Alignment(excelizestyle.AlignmentHorizontalCenter, excelizestyle.AlignmentVerticalCenter, true),
+			})
+			if err != nil {
+				return err
+			}
+			values[colIdx-1] = excelize.Cell{
+				StyleID: styleID,
+				Value:   fmt.Sprintf("test%d-%d", rowIdx, colIdx),
+			}
+		}
+		cell, err := excelize.CoordinatesToCellName(1, rowIdx)
+		if err != nil {
+			return err
+		}
+		if err := sw.SetRow(cell, values); err != nil {
+			return err
+		}
+	}
+	if err := sw.Flush(); err != nil {
+		return err
+	}
+	return f.Write(w)
+}
+
+func benchExcelizeam(w io.Writer, rows, cols int) error {
+	e, err := excelizeam.New("test")
+	if err != nil {
+		return err
+	}
+
+	for rowIdx := 1; rowIdx <= rows; rowIdx++ {
+		for colIdx := 1; colIdx <= cols; colIdx++ {
+			if err := e.SetCellValue(colIdx, rowIdx, fmt.Sprintf("test%d-%d", rowIdx, colIdx), &excelize.Style{
+				Border:    excelizestyle.BorderAround(excelizestyle.BorderStyleContinuous2, excelizestyle.BorderColorBlack),
+				Font:      &excelize.Font{Size: 12, Bold: true},
+				Alignment: excelizestyle.Alignment(excelizestyle.AlignmentHorizontalCenter, excelizestyle.AlignmentVerticalCenter, true),
+			}, false, false); err != nil {
+				return err
+			}
+		}
+	}
+	return e.Write(w)
+}
+
+func benchExcelizeamAsync(w io.Writer, rows, cols int) error {
+	e, err := excelizeam.New("test")
+	if err != nil {
+		return err
+	}
+
+	for rowIdx := 1; rowIdx <= rows; rowIdx++ {
+		for colIdx := 1; colIdx <= cols; colIdx++ {
+			e.SetCellValueAsync(colIdx, rowIdx, fmt.Sprintf("test%d-%d", rowIdx, colIdx), &excelize.Style{
+				Border:    excelizestyle.BorderAround(excelizestyle.BorderStyleContinuous2, excelizestyle.BorderColorBlack),
+				Font:      &excelize.Font{Size: 12, Bold: true},
+				Alignment: excelizestyle.Alignment(excelizestyle.AlignmentHorizontalCenter, excelizestyle.AlignmentVerticalCenter, true),
+			}, false)
+		}
+	}
+
+	return e.Write(w)
+}
+
+// benchExcelizeamOverride Every cell gets a distinct style, then every cell is overridden by border ranges
+func benchExcelizeamOverride(w io.Writer, rows, cols int) error {
+	e, err := excelizeam.New("test")
+	if err != nil {
+		return err
+	}
+
+	for rowIdx := 1; rowIdx <= rows; rowIdx++ {
+		for colIdx := 1; colIdx <= cols; colIdx++ {
+			if err := e.SetCellValue(colIdx, rowIdx, fmt.Sprintf("test%d-%d", rowIdx, colIdx), &excelize.Style{
+				Font: &excelize.Font{Size: 12, Color: fmt.Sprintf("#%06X", rowIdx*cols+colIdx)},
+			}, false, false); err != nil {
+				return err
+			}
+		}
+	}
+	for rowIdx := 1; rowIdx <= rows; rowIdx += 10 {
+		if err := e.SetBorderRange(1, rowIdx, cols, rowIdx+9, excelizeam.BorderRange{
+			Top:    &excelizeam.BorderItem{Style: excelizestyle.BorderStyleContinuous2, Color: excelizestyle.BorderColorBlack},
+			Bottom: &excelizeam.BorderItem{Style: excelizestyle.BorderStyleContinuous2, Color: excelizestyle.BorderColorBlack},
+			Left:   &excelizeam.BorderItem{Style: excelizestyle.BorderStyleContinuous2, Color: excelizestyle.BorderColorBlack},
+			Right:  &excelizeam.BorderItem{Style: excelizestyle.BorderStyleContinuous2, Color: excelizestyle.BorderColorBlack},
+			Inside: &excelizeam.BorderItem{Style: excelizestyle.BorderStyleDash1, Color: excelizestyle.BorderColorBlack},
+		}, true); err != nil {
+			return err
+		}
+	}
+	return e.Write(w)
+}
+
+func benchExcelizeamOverrideAsync(w io.Writer, rows, cols int) error {
+	e, err := excelizeam.New("test")
+	if err != nil {
+		return err
+	}
+
+	for rowIdx := 1; rowIdx <= rows; rowIdx++ {
+		for colIdx := 1; colIdx <= cols; colIdx++ {
+			e.SetCellValueAsync(colIdx, rowIdx, fmt.Sprintf("test%d-%d", rowIdx, colIdx), &excelize.Style{
+				Font: &excelize.Font{Size: 12, Color: fmt.Sprintf("#%06X", rowIdx*cols+colIdx)},
+			}, false)
+		}
+	}
+	if err := e.Wait(); err != nil {
+		return err
+	}
+	for rowIdx := 1; rowIdx <= rows; rowIdx += 10 {
+		e.SetBorderRangeAsync(1, rowIdx, cols, rowIdx+9, excelizeam.BorderRange{
+			Top:    &excelizeam.BorderItem{Style: excelizestyle.BorderStyleContinuous2, Color: excelizestyle.BorderColorBlack},
+			Bottom: &excelizeam.BorderItem{Style: excelizestyle.BorderStyleContinuous2, Color: excelizestyle.BorderColorBlack},
+			Left:   &excelizeam.BorderItem{Style: excelizestyle.BorderStyleContinuous2, Color: excelizestyle.BorderColorBlack},
+			Right:  &excelizeam.BorderItem{Style: excelizestyle.BorderStyleContinuous2, Color: excelizestyle.BorderColorBlack},
+			Inside: &excelizeam.BorderItem{Style: excelizestyle.BorderStyleDash1, Color: excelizestyle.BorderColorBlack},
+		}, true)
+	}
 	return e.Write(w)
 }
 
@@ -0,0 +1,248 @@
+package excelizeam
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var (
+	ErrArrayFormulaRef = errors.New("array formula ref must start at the formula cell")
+)
+
+// Formula Value of a formula cell
+type Formula struct {
+	// Expr formula such as "SUM(A1:A10)", the leading "=" is optional
+	Expr string
+	// Value cached result of the formula shown until the workbook is recalculated, nil leaves the cell without result
+	Value interface{}
+	// Array marks an array formula
+	Array bool
+	// Ref range of the array formula such as "C1:C3", whose top left cell must be the formula cell
+	// Empty is the formula cell only.
+	Ref string
+}
+
+// CSVFormulaMode What CSVRecords exports for formula cells
+type CSVFormulaMode int
+
+const (
+	// CSVFormulaValue Export the cached result of the formula
+	CSVFormulaValue CSVFormulaMode = iota
+	// CSVFormulaExpr Export the formula prefixed with "="
+	CSVFormulaExpr
+)
+
+func (e *excelizeam) SetCellFormulaAsync(colIndex, rowIndex int, formula Formula, style *excelize.Style) {
+	policy := e.overridePolicy(OverridePolicy{})
+	e.async.Go(func(seq uint64) error {
+		return e.setCellValue("SetCellFormulaAsync", colIndex, rowIndex, formula, style, policy, policy, e.addPendingUpdate(seq))
+	})
+}
+
+func (e *excelizeam) SetCellFormula(colIndex, rowIndex int, formula Formula, style *excelize.Style) error {
+	if err := e.async.Wait(); err != nil {
+		return err
+	}
+	policy := e.overridePolicy(OverridePolicy{})
+	return e.setCellValue("SetCellFormula", colIndex, rowIndex, formula, style, policy, policy, e.applyUpdate)
+}
+
+func (e *excelizeam) SetCSVFormulaMode(mode CSVFormulaMode) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.csvFormulaMode = mode
+}
+
+// streamCell Make the cell written to the StreamWriter
+func streamCell(c Cell) excelize.Cell {
+	if f, ok := c.Value.(Formula); ok {
+		return excelize.Cell{StyleID: c.StyleID, Formula: strings.TrimPrefix(f.Expr, "="), Value: f.Value}
+	}
+	return excelize.Cell{StyleID: c.StyleID, Value: c.Value}
+}
+
+// arrayFormulaRef Get the ref of the array formula of the cell from ref, which is the cell itself when empty
+func arrayFormulaRef(colIndex, rowIndex int, ref string) (string, error) {
+	cellName, err := excelize.CoordinatesToCellName(colIndex, rowIndex)
+	if err != nil {
+		return "", err
+	}
+	if ref == "" {
+		return cellName, nil
+	}
+	start, end, ok := strings.Cut(ref, ":")
+	if !ok {
+		end = start
+	}
+	startCol, startRow, err := excelize.CellNameToCoordinates(start)
+	if err != nil {
+		return "", err
+	}
+	endCol, endRow, err := excelize.CellNameToCoordinates(end)
+	if err != nil {
+		return "", err
+	}
+	if startCol != colIndex || startRow != rowIndex || endCol < startCol || endRow < startRow {
+		return "", ErrArrayFormulaRef
+	}
+	if endCol == startCol && endRow == startRow {
+		return cellName, nil
+	}
+	endName, err := excelize.CoordinatesToCellName(endCol, endRow)
+	if err != nil {
+		return "", err
+	}
+	return cellName + ":" + endName, nil
+}
+
+// streamedSheetPath Get the path of the worksheet whose StreamWriter has just been flushed
+// excelize.StreamWriter.Flush drops its worksheet from excelize.File.Sheet, so it is the one of paths, taken before the flush, which is gone.
+func streamedSheetPath(f *excelize.File, paths []string) (string, error) {
+	for _, path := range paths {
+		if _, ok := f.Sheet.Load(path); !ok {
+			return path, nil
+		}
+	}
+	return "", errors.New("worksheet of the array formulas not found")
+}
+
+// sheetPaths Get the paths of the worksheets loaded in f
+func sheetPaths(f *excelize.File) []string {
+	var paths []string
+	f.Sheet.Range(func(path, _ interface{}) bool {
+		paths = append(paths, path.(string))
+		return true
+	})
+	return paths
+}
+
+// arrayFormulaZipWriter excelize.ZipWriter which turns the formulas written by the StreamWriters into array formulas
+// excelize.StreamWriter can only write plain formulas, and the worksheets it has written cannot be changed through excelize.File,
+// so the array type and ref are added to the formula elements of the cells in arrayFormulas, keyed by the worksheet path and the cell name, as they are saved.
+type arrayFormulaZipWriter struct {
+	excelize.ZipWriter
+	arrayFormulas map[string]map[string]string
+	// w writer of the worksheet being saved
+	w *arrayFormulaWriter
+}
+
+func (zw *arrayFormulaZipWriter) Create(name string) (io.Writer, error) {
+	if err := zw.flush(); err != nil {
+		return nil, err
+	}
+	w, err := zw.ZipWriter.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	refs, ok := zw.arrayFormulas[name]
+	if !ok {
+		return w, nil
+	}
+	zw.w = &arrayFormulaWriter{w: w, refs: refs}
+	return zw.w, nil
+}
+
+func (zw *arrayFormulaZipWriter) Close() error {
+	if err := zw.flush(); err != nil {
+		return err
+	}
+	return zw.ZipWriter.Close()
+}
+
+// flush Write the rest of the worksheet being saved
+func (zw *arrayFormulaZipWriter) flush() error {
+	if zw.w == nil {
+		return nil
+	}
+	w := zw.w
+	zw.w = nil
+	_, err := w.w.Write(w.buf)
+	return err
+}
+
+// arrayFormulaWriter io.Writer of a worksheet which adds the array type and ref of refs to the formula elements of the cells
+// The worksheet is written tag by tag, the tag cut off at the end of a write is held until the next one.
+type arrayFormulaWriter struct {
+	w    io.Writer
+	refs map[string]string
+	buf  []byte
+	out  []byte
+	// ref ref of the array formula of the cell whose tag has just been written
+	ref string
+}
+
+func (w *arrayFormulaWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	w.out = w.out[:0]
+	rest := w.buf
+	for {
+		start := bytes.IndexByte(rest, '<')
+		if start < 0 {
+			w.out = append(w.out, rest...)
+			rest = nil
+			break
+		}
+		end := bytes.IndexByte(rest[start:], '>')
+		if end < 0 {
+			w.out = append(w.out, rest[:start]...)
+			rest = rest[start:]
+			break
+		}
+		w.out = append(w.out, rest[:start]...)
+		w.writeTag(rest[start : start+end+1])
+		rest = rest[start+end+1:]
+	}
+	w.buf = w.buf[:copy(w.buf, rest)]
+	if _, err := w.w.Write(w.out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// writeTag Write the tag to out, as an array formula when it is the formula of a cell of refs
+func (w *arrayFormulaWriter) writeTag(tag []byte) {
+	if w.ref != "" && string(tag) == "<f>" {
+		w.out = append(w.out, `<f t="array" ref="`...)
+		w.out = append(w.out, w.ref...)
+		w.out = append(w.out, `">`...)
+		w.ref = ""
+		return
+	}
+	w.out = append(w.out, tag...)
+	w.ref = ""
+	if !bytes.HasPrefix(tag, []byte("<c ")) {
+		return
+	}
+	_, attr, ok := bytes.Cut(tag, []byte(` r="`))
+	if !ok {
+		return
+	}
+	cellName, _, ok := bytes.Cut(attr, []byte(`"`))
+	if !ok {
+		return
+	}
+	w.ref = w.refs[string(cellName)]
+}
+
+// csvValue Make the csv field of the cell value
+func csvValue(value interface{}, mode CSVFormulaMode) string {
+	if runs, ok := value.([]excelize.RichTextRun); ok {
+		return richTextString(runs)
+	}
+	f, ok := value.(Formula)
+	if !ok {
+		return fmt.Sprintf("%v", value)
+	}
+	if mode == CSVFormulaExpr {
+		return "=" + strings.TrimPrefix(f.Expr, "=")
+	}
+	if f.Value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", f.Value)
+}
@@ -0,0 +1,92 @@
+package excelizeam
+
+import (
+	"errors"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var (
+	ErrInvalidHyperlinkType = errors.New("invalid hyperlink type")
+)
+
+// HyperlinkType Kind of the target of a hyperlink
+type HyperlinkType string
+
+const (
+	// HyperlinkTypeExternal Link to a URL such as "https://example.com"
+	HyperlinkTypeExternal HyperlinkType = "External"
+	// HyperlinkTypeLocation Link to a location in the workbook such as "Sheet1!A1"
+	HyperlinkTypeLocation HyperlinkType = "Location"
+)
+
+// DefaultHyperlinkStyle Style applied to hyperlink cells unless changed by WithHyperlinkStyle
+func DefaultHyperlinkStyle() *excelize.Style {
+	return &excelize.Style{Font: &excelize.Font{Color: "0563C1", Underline: "single"}}
+}
+
+type hyperlink struct {
+	link     string
+	linkType HyperlinkType
+	display  string
+	tooltip  string
+}
+
+func (e *excelizeam) SetCellHyperlinkAsync(colIndex, rowIndex int, link string, linkType HyperlinkType, display, tooltip string) {
+	policy := e.overridePolicy(OverridePolicy{})
+	e.async.Go(func(seq uint64) error {
+		return e.setCellHyperlink("SetCellHyperlinkAsync", colIndex, rowIndex, link, linkType, display, tooltip, policy, e.addPendingUpdate(seq))
+	})
+}
+
+func (e *excelizeam) SetCellHyperlink(colIndex, rowIndex int, link string, linkType HyperlinkType, display, tooltip string) error {
+	if err := e.async.Wait(); err != nil {
+		return err
+	}
+	policy := e.overridePolicy(OverridePolicy{})
+	return e.setCellHyperlink("SetCellHyperlink", colIndex, rowIndex, link, linkType, display, tooltip, policy, e.applyUpdate)
+}
+
+func (e *excelizeam) setCellHyperlink(op string, colIndex, rowIndex int, link string, linkType HyperlinkType, display, tooltip string, valuePolicy OverridePolicy, apply applyUpdateFunc) error {
+	if linkType != HyperlinkTypeExternal && linkType != HyperlinkTypeLocation {
+		return newCellError(op, colIndex, rowIndex, ErrInvalidHyperlinkType)
+	}
+	if err := e.checkMaxIndex(rowIndex, colIndex, rowIndex); err != nil {
+		return newCellError(op, colIndex, rowIndex, err)
+	}
+	u := cellUpdate{
+		op:          op,
+		colIndex:    colIndex,
+		rowIndex:    rowIndex,
+		valuePolicy: valuePolicy,
+		// the font of the link is laid over the style of the cell
+		stylePolicy: OverridePolicyMerge,
+		hyperlink: &hyperlink{
+			link:     link,
+			linkType: linkType,
+			display:  display,
+			tooltip:  tooltip,
+		},
+	}
+	if display != "" {
+		u.value = display
+	}
+	if err := u.setStyle(e.wb.opts.hyperlinkStyle); err != nil {
+		return newCellError(op, colIndex, rowIndex, err)
+	}
+	return apply(u)
+}
+
+// setCellHyperlink Set the hyperlink to the worksheet of the sheet, which is written by StreamWriter.Flush
+func (wb *workbook) setCellHyperlink(sheet, cell string, h hyperlink) error {
+	var opts excelize.HyperlinkOpts
+	if h.display != "" {
+		opts.Display = &h.display
+	}
+	if h.tooltip != "" {
+		opts.Tooltip = &h.tooltip
+	}
+	wb.fileMu.Lock()
+	defer wb.fileMu.Unlock()
+	return wb.file.SetCellHyperLink(sheet, cell, h.link, string(h.linkType), opts)
+}
@@ -0,0 +1,317 @@
+package excelizeam
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// excelizeDefaultColWidth Column width excelize reports for a column without width in a sheet without default width
+const excelizeDefaultColWidth = 9.140625
+
+// excelizeDefaultRowHeight Row height excelize reports for a row without height in a sheet without default height
+const excelizeDefaultRowHeight = 15
+
+func (e *excelizeam) Import(f *excelize.File, sheet string) error {
+	if err := e.async.Wait(); err != nil {
+		return err
+	}
+	if err := e.checkFinished(); err != nil {
+		return err
+	}
+	maxRow, maxCol, err := sheetExtent(f, sheet)
+	if err != nil {
+		return err
+	}
+	rowStyleIDs, err := e.importRowOptions(f, sheet, maxRow, maxCol)
+	if err != nil {
+		return err
+	}
+	if err := e.importCells(f, sheet, maxRow, maxCol, rowStyleIDs); err != nil {
+		return err
+	}
+
+	mergeCells, err := f.GetMergeCells(sheet, true)
+	if err != nil {
+		return err
+	}
+	for _, mergeCell := range mergeCells {
+		endColIdx, _, err := excelize.CellNameToCoordinates(mergeCell.GetEndAxis())
+		if err != nil {
+			return err
+		}
+		if maxCol < endColIdx {
+			maxCol = endColIdx
+		}
+		if err := e.sw.MergeCell(mergeCell.GetStartAxis(), mergeCell.GetEndAxis()); err != nil {
+			return err
+		}
+	}
+	return e.importColWidths(f, sheet, maxCol)
+}
+
+// sheetExtent Get the index of the last row element and the last column of the cell elements in the sheet of f
+// Unlike the dimension of the sheet, which may be stale, the extent covers every cell with style only.
+func sheetExtent(f *excelize.File, sheet string) (maxRow, maxCol int, err error) {
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return 0, 0, err
+	}
+	// Next goes through every row up to the last row element
+	for rows.Next() {
+		maxRow++
+	}
+	if err := rows.Close(); err != nil {
+		return 0, 0, err
+	}
+	cols, err := f.Cols(sheet)
+	if err != nil {
+		return 0, 0, err
+	}
+	// Next goes through every column up to the last column of the cell elements
+	for cols.Next() {
+		maxCol++
+	}
+	return maxRow, maxCol, nil
+}
+
+// importCells Store the values and styles of the cells of the sheet of f within maxRow and maxCol
+// A cell without value whose style is the style of its row in rowStyleIDs only inherits it, so it is left to the row.
+// The imported cells replace the cells already stored at the same coordinates.
+func (e *excelizeam) importCells(f *excelize.File, sheet string, maxRow, maxCol int, rowStyleIDs []int) error {
+	rows, err := f.GetRows(sheet, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return err
+	}
+
+	styleIDs := make(map[int]int)
+	for rowIdx := 1; rowIdx <= maxRow; rowIdx++ {
+		if err := e.wb.ctx.Err(); err != nil {
+			return err
+		}
+		for colIdx := 1; colIdx <= maxCol; colIdx++ {
+			cellName, err := excelize.CoordinatesToCellName(colIdx, rowIdx)
+			if err != nil {
+				return err
+			}
+			srcStyleID, err := f.GetCellStyle(sheet, cellName)
+			if err != nil {
+				return err
+			}
+			var raw string
+			if rowIdx <= len(rows) && colIdx <= len(rows[rowIdx-1]) {
+				raw = rows[rowIdx-1][colIdx-1]
+			}
+			formula, err := f.GetCellFormula(sheet, cellName)
+			if err != nil {
+				return err
+			}
+			if raw == "" && formula == "" && (srcStyleID == 0 || srcStyleID == rowStyleIDs[rowIdx-1]) {
+				continue
+			}
+
+			styleID, ok := styleIDs[srcStyleID]
+			if !ok {
+				styleID, err = e.wb.importStyle(f, srcStyleID)
+				if err != nil {
+					return err
+				}
+				styleIDs[srcStyleID] = styleID
+			}
+			var value interface{}
+			if raw != "" {
+				value, err = importCellValue(f, sheet, cellName, raw)
+				if err != nil {
+					return err
+				}
+			}
+			if formula != "" {
+				value = Formula{Expr: formula, Value: value}
+			}
+			if err := e.checkMaxIndex(rowIdx, colIdx, rowIdx); err != nil {
+				return newCellError("Import", colIdx, rowIdx, err)
+			}
+			if err := e.cellStore.Update(colIdx, rowIdx, func(cell *Cell, exists bool) error {
+				cell.StyleID = styleID
+				cell.Value = value
+				return nil
+			}); err != nil {
+				return newCellError("Import", colIdx, rowIdx, err)
+			}
+		}
+	}
+	return nil
+}
+
+// importRowOptions Set the heights, visibility, outline levels and styles of the rows of the sheet of f up to maxRow which differ from the defaults
+// and get the style IDs of the rows in f.
+// The imported options replace the options already set to the rows.
+func (e *excelizeam) importRowOptions(f *excelize.File, sheet string, maxRow, maxCol int) ([]int, error) {
+	props, err := f.GetSheetProps(sheet)
+	if err != nil {
+		return nil, err
+	}
+	// same fallback as excelize.File.GetRowHeight
+	defaultHeight := float64(excelizeDefaultRowHeight)
+	if props.CustomHeight != nil && *props.CustomHeight && props.DefaultRowHeight != nil {
+		defaultHeight = *props.DefaultRowHeight
+	}
+	// the style of a row is the style excelize reports for a cell of the row without cell element,
+	// which falls back to the style of the column when the row has none
+	styleColIdx := maxCol + 1
+	var (
+		styleCellCol    string
+		styleColStyleID int
+	)
+	if styleColIdx <= excelize.MaxColumns {
+		if styleCellCol, err = excelize.ColumnNumberToName(styleColIdx); err != nil {
+			return nil, err
+		}
+		if styleColStyleID, err = f.GetColStyle(sheet, styleCellCol); err != nil {
+			return nil, err
+		}
+	}
+
+	rowStyleIDs := make([]int, maxRow)
+	seq := e.async.lastSeq()
+	for rowIdx := 1; rowIdx <= maxRow; rowIdx++ {
+		height, err := f.GetRowHeight(sheet, rowIdx)
+		if err != nil {
+			return nil, err
+		}
+		if height != defaultHeight {
+			if err := e.setRowHeight("Import", rowIdx, height, seq); err != nil {
+				return nil, err
+			}
+		}
+		visible, err := f.GetRowVisible(sheet, rowIdx)
+		if err != nil {
+			return nil, err
+		}
+		if !visible {
+			if err := e.setRowHidden("Import", rowIdx, true, seq); err != nil {
+				return nil, err
+			}
+		}
+		level, err := f.GetRowOutlineLevel(sheet, rowIdx)
+		if err != nil {
+			return nil, err
+		}
+		if level > 0 {
+			if err := e.setRowOutlineLevel("Import", rowIdx, int(level), seq); err != nil {
+				return nil, err
+			}
+		}
+		if styleCellCol == "" {
+			continue
+		}
+		srcStyleID, err := f.GetCellStyle(sheet, styleCellCol+strconv.Itoa(rowIdx))
+		if err != nil {
+			return nil, err
+		}
+		if srcStyleID == 0 || srcStyleID == styleColStyleID {
+			continue
+		}
+		styleID, err := e.wb.importStyle(f, srcStyleID)
+		if err != nil {
+			return nil, &RowError{Op: "Import", Row: rowIdx, Err: err}
+		}
+		if err := e.setRowOption("Import", rowIdx, seq, rowFieldStyle, func(opts *excelize.RowOpts) {
+			opts.StyleID = styleID
+		}); err != nil {
+			return nil, err
+		}
+		rowStyleIDs[rowIdx-1] = srcStyleID
+	}
+	return rowStyleIDs, nil
+}
+
+// importColWidths Set the widths of the columns up to maxCol which differ from the default width of the sheet of f
+func (e *excelizeam) importColWidths(f *excelize.File, sheet string, maxCol int) error {
+	props, err := f.GetSheetProps(sheet)
+	if err != nil {
+		return err
+	}
+	// same fallback as excelize.File.GetColWidth, DefaultColWidth is only set when the sheet has the format properties
+	defaultWidth := excelizeDefaultColWidth
+	if props.DefaultColWidth != nil {
+		if *props.DefaultColWidth > 0 {
+			defaultWidth = *props.DefaultColWidth
+		} else if *props.BaseColWidth > 0 {
+			defaultWidth = float64(*props.BaseColWidth)
+		}
+	}
+	for colIdx := 1; colIdx <= maxCol; colIdx++ {
+		colName, err := excelize.ColumnNumberToName(colIdx)
+		if err != nil {
+			return err
+		}
+		width, err := f.GetColWidth(sheet, colName)
+		if err != nil {
+			return err
+		}
+		if width == defaultWidth {
+			continue
+		}
+		if err := e.sw.SetColWidth(colIdx, colIdx, width); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importCellValue Convert the raw value of the cell to the type it is stored with
+func importCellValue(f *excelize.File, sheet, cell, raw string) (interface{}, error) {
+	cellType, err := f.GetCellType(sheet, cell)
+	if err != nil {
+		return nil, err
+	}
+	switch cellType {
+	case excelize.CellTypeSharedString, excelize.CellTypeInlineString:
+		runs, err := f.GetCellRichText(sheet, cell)
+		if err != nil {
+			return nil, err
+		}
+		for _, run := range runs {
+			if run.Font != nil {
+				return runs, nil
+			}
+		}
+	case excelize.CellTypeBool:
+		return raw == "1" || strings.EqualFold(raw, "TRUE"), nil
+	case excelize.CellTypeNumber, excelize.CellTypeUnset:
+		if v, err := strconv.ParseFloat(raw, 64); err == nil {
+			return v, nil
+		}
+	}
+	return raw, nil
+}
+
+// importStyle Get the style ID in the workbook of the style with srcStyleID in f
+// Styles of the workbook's own file keep their style ID, so that the styles of a template can be reused as is.
+func (wb *workbook) importStyle(f *excelize.File, srcStyleID int) (int, error) {
+	if srcStyleID == 0 {
+		return 0, nil
+	}
+	if f != wb.file {
+		style, err := f.GetStyle(srcStyleID)
+		if err != nil {
+			return 0, err
+		}
+		return wb.getStyleID(style)
+	}
+	if _, ok := wb.styleStore.Load(srcStyleID); ok {
+		return srcStyleID, nil
+	}
+	style, err := f.GetStyle(srcStyleID)
+	if err != nil {
+		return 0, err
+	}
+	key, err := styleKey(style)
+	if err != nil {
+		return 0, err
+	}
+	wb.styleStore.Store(key, StoredStyle{StyleID: srcStyleID, Style: style})
+	return srcStyleID, nil
+}
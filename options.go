@@ -0,0 +1,136 @@
+package excelizeam
+
+import (
+	"context"
+
+	"github.com/tomtwinkle/excelizeam/excelizestyle"
+	"github.com/xuri/excelize/v2"
+)
+
+// Option Configure the workbook and sheets created by New, NewWithContext, NewWorkbook and NewWorkbookWithContext
+// Sheet options are applied to every sheet added to the workbook.
+type Option func(o *options)
+
+type options struct {
+	ctx context.Context
+
+	// workbook
+	excelizeOptions []excelize.Options
+	workbookProps   *excelize.WorkbookPropsOptions
+
+	// sheet
+	asyncWorkers      int
+	asyncBatchSize    int
+	asyncErrorLimit   int
+	defaultBorder     *BorderItem
+	defaultBorderCols int
+	overridePolicy    OverridePolicy
+	csvFormulaMode    CSVFormulaMode
+	hyperlinkStyle    *excelize.Style
+}
+
+func newOptions(opts []Option) (options, error) {
+	o := options{
+		ctx:             context.Background(),
+		asyncWorkers:    DefaultAsyncWorkers(),
+		asyncBatchSize:  DefaultAsyncBatchSize,
+		asyncErrorLimit: DefaultAsyncErrorLimit,
+		overridePolicy:  OverridePolicyError,
+		hyperlinkStyle:  DefaultHyperlinkStyle(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.asyncWorkers < 1 || o.asyncBatchSize < 1 || o.asyncErrorLimit < 0 {
+		return options{}, ErrInvalidAsyncLimit
+	}
+	if o.overridePolicy.isDefault() {
+		o.overridePolicy = OverridePolicyError
+	}
+	return o, nil
+}
+
+// WithContext Bind the workbook to ctx
+// Once ctx is done, queued asynchronous operations are skipped and Write returns ctx.Err().
+func WithContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.ctx = ctx
+	}
+}
+
+// WithExcelizeOptions Set the options passed to excelize.NewFile
+func WithExcelizeOptions(opts excelize.Options) Option {
+	return func(o *options) {
+		o.excelizeOptions = append(o.excelizeOptions, opts)
+	}
+}
+
+// WithWorkbookProps Set the workbook properties such as the date system and the code name
+func WithWorkbookProps(props excelize.WorkbookPropsOptions) Option {
+	return func(o *options) {
+		o.workbookProps = &props
+	}
+}
+
+// WithDate1904 Use the 1904 date system instead of the 1900 date system
+func WithDate1904() Option {
+	return func(o *options) {
+		if o.workbookProps == nil {
+			o.workbookProps = &excelize.WorkbookPropsOptions{}
+		}
+		date1904 := true
+		o.workbookProps.Date1904 = &date1904
+	}
+}
+
+// WithAsyncLimit Set the number of workers running asynchronous operations
+// and the number of operations handed to a worker at once, see Sheet.SetAsyncLimit
+func WithAsyncLimit(workers, batchSize int) Option {
+	return func(o *options) {
+		o.asyncWorkers = workers
+		o.asyncBatchSize = batchSize
+	}
+}
+
+// WithAsyncErrorLimit Set the number of asynchronous operation errors returned by Wait, see Sheet.SetAsyncErrorLimit
+func WithAsyncErrorLimit(limit int) Option {
+	return func(o *options) {
+		o.asyncErrorLimit = limit
+	}
+}
+
+// WithDefaultBorderStyle Set default cell border, see Sheet.SetDefaultBorderStyle
+func WithDefaultBorderStyle(style excelizestyle.BorderStyle, color excelizestyle.BorderColor) Option {
+	return func(o *options) {
+		o.defaultBorder = &BorderItem{Style: style, Color: color}
+	}
+}
+
+// WithDefaultBorderColumns Set the number of columns the default border covers at least, see Sheet.SetDefaultBorderColumns
+func WithDefaultBorderColumns(colCount int) Option {
+	return func(o *options) {
+		o.defaultBorderCols = colCount
+	}
+}
+
+// WithOverridePolicy Set the default policy of the *WithPolicy methods, see Sheet.SetOverridePolicy
+func WithOverridePolicy(policy OverridePolicy) Option {
+	return func(o *options) {
+		o.overridePolicy = policy
+	}
+}
+
+// WithCSVFormulaMode Set what CSVRecords exports for formula cells, see Sheet.SetCSVFormulaMode
+func WithCSVFormulaMode(mode CSVFormulaMode) Option {
+	return func(o *options) {
+		o.csvFormulaMode = mode
+	}
+}
+
+// WithHyperlinkStyle Set the style applied to hyperlink cells, nil leaves their style as is
+// Default is DefaultHyperlinkStyle.
+func WithHyperlinkStyle(style *excelize.Style) Option {
+	return func(o *options) {
+		o.hyperlinkStyle = style
+	}
+}
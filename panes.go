@@ -0,0 +1,80 @@
+package excelizeam
+
+import (
+	"github.com/xuri/excelize/v2"
+)
+
+func (e *excelizeam) SetFreezePanes(cols, rows int) error {
+	if cols < 0 || rows < 0 {
+		return excelize.ErrParameterInvalid
+	}
+	if cols == 0 && rows == 0 {
+		return e.SetPanes(nil)
+	}
+	topLeftCell, err := excelize.CoordinatesToCellName(cols+1, rows+1)
+	if err != nil {
+		return err
+	}
+	activePane := "bottomRight"
+	if cols == 0 {
+		activePane = "bottomLeft"
+	} else if rows == 0 {
+		activePane = "topRight"
+	}
+	return e.SetPanes(&excelize.Panes{
+		Freeze:      true,
+		XSplit:      cols,
+		YSplit:      rows,
+		TopLeftCell: topLeftCell,
+		ActivePane:  activePane,
+		Selection: []excelize.Selection{
+			{SQRef: topLeftCell, ActiveCell: topLeftCell, Pane: activePane},
+		},
+	})
+}
+
+func (e *excelizeam) SetSplitPanes(xSplit, ySplit int) error {
+	if xSplit < 0 || ySplit < 0 {
+		return excelize.ErrParameterInvalid
+	}
+	if xSplit == 0 && ySplit == 0 {
+		return e.SetPanes(nil)
+	}
+	activePane := "bottomRight"
+	if xSplit == 0 {
+		activePane = "bottomLeft"
+	} else if ySplit == 0 {
+		activePane = "topRight"
+	}
+	return e.SetPanes(&excelize.Panes{
+		Split:       true,
+		XSplit:      xSplit,
+		YSplit:      ySplit,
+		TopLeftCell: "A1",
+		ActivePane:  activePane,
+		Selection: []excelize.Selection{
+			{SQRef: "A1", ActiveCell: "A1", Pane: activePane},
+		},
+	})
+}
+
+func (e *excelizeam) SetPanes(panes *excelize.Panes) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.finishedErr != nil {
+		return e.finishedErr
+	}
+	// the StreamWriter takes panes only before the first row, rows flushed without cells or options are not written to it
+	if e.rowWritten {
+		return &RowFlushedError{RowIndex: 1, FlushedRowIndex: e.flushedRow}
+	}
+	if panes == nil {
+		panes = &excelize.Panes{}
+	} else {
+		p := *panes
+		p.Selection = append([]excelize.Selection(nil), panes.Selection...)
+		panes = &p
+	}
+	e.panes = panes
+	return nil
+}
@@ -0,0 +1,88 @@
+package excelizeam
+
+import (
+	"github.com/xuri/excelize/v2"
+)
+
+// OverridePolicy How a value or style is set to a cell which already has one
+// The zero OverridePolicy uses the default policy of the sheet set by SetOverridePolicy.
+type OverridePolicy struct {
+	mode      overrideMode
+	styleFunc func(existing, style *excelize.Style) *excelize.Style
+}
+
+type overrideMode int
+
+const (
+	overrideModeDefault overrideMode = iota
+	overrideModeError
+	overrideModeKeepExisting
+	overrideModeReplace
+	overrideModeMerge
+	overrideModeFunc
+)
+
+var (
+	// OverridePolicyError Fail with ErrOverrideCellValue or ErrOverrideCellStyle
+	OverridePolicyError = OverridePolicy{mode: overrideModeError}
+	// OverridePolicyKeepExisting Leave the existing value or style as is
+	OverridePolicyKeepExisting = OverridePolicy{mode: overrideModeKeepExisting}
+	// OverridePolicyReplace Replace the existing value or style
+	OverridePolicyReplace = OverridePolicy{mode: overrideModeReplace}
+	// OverridePolicyMerge Replace the existing value and merge the style into the existing style
+	// Only the fields set in the new style are overridden, and borders are merged by position.
+	OverridePolicyMerge = OverridePolicy{mode: overrideModeMerge}
+)
+
+// OverridePolicyFunc Replace the existing value and the existing style with the style returned by fn
+// fn gets copies of the existing style and the new style. When fn returns nil, the existing style is left as is.
+// A nil fn is the same as OverridePolicyKeepExisting.
+func OverridePolicyFunc(fn func(existing, style *excelize.Style) *excelize.Style) OverridePolicy {
+	if fn == nil {
+		return OverridePolicyKeepExisting
+	}
+	return OverridePolicy{mode: overrideModeFunc, styleFunc: fn}
+}
+
+// overridePolicy Policy of the override flag taken by the methods without a policy
+func overridePolicy(override bool) OverridePolicy {
+	if override {
+		return OverridePolicyMerge
+	}
+	return OverridePolicyError
+}
+
+func (p OverridePolicy) isDefault() bool {
+	return p.mode == overrideModeDefault
+}
+
+// overrideValue Get the value to be stored to a cell which already has the value existing
+func (p OverridePolicy) overrideValue(existing, value interface{}) (interface{}, error) {
+	switch p.mode {
+	case overrideModeKeepExisting:
+		return existing, nil
+	case overrideModeReplace, overrideModeMerge, overrideModeFunc:
+		return value, nil
+	default:
+		return nil, ErrOverrideCellValue
+	}
+}
+
+// overrideStyle Get the style to be stored to a cell which already has the style existing
+func (p OverridePolicy) overrideStyle(existing, style *excelize.Style) (*excelize.Style, error) {
+	switch p.mode {
+	case overrideModeKeepExisting:
+		return existing, nil
+	case overrideModeReplace:
+		return style, nil
+	case overrideModeMerge:
+		return mergeStyle(existing, style), nil
+	case overrideModeFunc:
+		if overridden := p.styleFunc(copyStyle(existing), copyStyle(style)); overridden != nil {
+			return overridden, nil
+		}
+		return existing, nil
+	default:
+		return nil, ErrOverrideCellStyle
+	}
+}
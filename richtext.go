@@ -0,0 +1,45 @@
+package excelizeam
+
+import (
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func (e *excelizeam) SetCellRichTextAsync(colIndex, rowIndex int, runs []excelize.RichTextRun, style *excelize.Style) {
+	policy := e.overridePolicy(OverridePolicy{})
+	runs = copyRichText(runs)
+	e.async.Go(func(seq uint64) error {
+		return e.setCellValue("SetCellRichTextAsync", colIndex, rowIndex, runs, style, policy, policy, e.addPendingUpdate(seq))
+	})
+}
+
+func (e *excelizeam) SetCellRichText(colIndex, rowIndex int, runs []excelize.RichTextRun, style *excelize.Style) error {
+	if err := e.async.Wait(); err != nil {
+		return err
+	}
+	policy := e.overridePolicy(OverridePolicy{})
+	return e.setCellValue("SetCellRichText", colIndex, rowIndex, copyRichText(runs), style, policy, policy, e.applyUpdate)
+}
+
+// copyRichText Copy the runs and their fonts, so that changes made by the caller afterwards are not stored
+func copyRichText(runs []excelize.RichTextRun) []excelize.RichTextRun {
+	copied := make([]excelize.RichTextRun, len(runs))
+	for i, run := range runs {
+		if run.Font != nil {
+			font := *run.Font
+			run.Font = &font
+		}
+		copied[i] = run
+	}
+	return copied
+}
+
+// richTextString Flatten the runs into plain text
+func richTextString(runs []excelize.RichTextRun) string {
+	var sb strings.Builder
+	for _, run := range runs {
+		sb.WriteString(run.Text)
+	}
+	return sb.String()
+}
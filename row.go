@@ -0,0 +1,158 @@
+package excelizeam
+
+import (
+	"fmt"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// RowError is returned when an operation on a row fails
+type RowError struct {
+	Op  string
+	Row int
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("%s row %d: %s", e.Op, e.Row, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+type rowField int
+
+const (
+	rowFieldHeight rowField = iota
+	rowFieldHidden
+	rowFieldOutlineLevel
+	rowFieldStyle
+	rowFieldCount
+)
+
+// rowOptions Options of a row passed to the StreamWriter with the row
+// seqs holds the sequence number of the operation which set each field,
+// so that asynchronous operations finishing out of order leave the field of the last call.
+type rowOptions struct {
+	opts excelize.RowOpts
+	seqs [rowFieldCount]uint64
+}
+
+func (e *excelizeam) SetRowHeight(rowIndex int, height float64) error {
+	if err := e.async.Wait(); err != nil {
+		return err
+	}
+	return e.setRowHeight("SetRowHeight", rowIndex, height, e.async.lastSeq())
+}
+
+func (e *excelizeam) SetRowHeightAsync(rowIndex int, height float64) {
+	e.async.Go(func(seq uint64) error {
+		return e.setRowHeight("SetRowHeightAsync", rowIndex, height, seq)
+	})
+}
+
+func (e *excelizeam) setRowHeight(op string, rowIndex int, height float64, seq uint64) error {
+	if height < 0 || height > excelize.MaxRowHeight {
+		return &RowError{Op: op, Row: rowIndex, Err: excelize.ErrMaxRowHeight}
+	}
+	return e.setRowOption(op, rowIndex, seq, rowFieldHeight, func(opts *excelize.RowOpts) {
+		opts.Height = height
+	})
+}
+
+func (e *excelizeam) SetRowHidden(rowIndex int, hidden bool) error {
+	if err := e.async.Wait(); err != nil {
+		return err
+	}
+	return e.setRowHidden("SetRowHidden", rowIndex, hidden, e.async.lastSeq())
+}
+
+func (e *excelizeam) SetRowHiddenAsync(rowIndex int, hidden bool) {
+	e.async.Go(func(seq uint64) error {
+		return e.setRowHidden("SetRowHiddenAsync", rowIndex, hidden, seq)
+	})
+}
+
+func (e *excelizeam) setRowHidden(op string, rowIndex int, hidden bool, seq uint64) error {
+	return e.setRowOption(op, rowIndex, seq, rowFieldHidden, func(opts *excelize.RowOpts) {
+		opts.Hidden = hidden
+	})
+}
+
+func (e *excelizeam) SetRowOutlineLevel(rowIndex int, level int) error {
+	if err := e.async.Wait(); err != nil {
+		return err
+	}
+	return e.setRowOutlineLevel("SetRowOutlineLevel", rowIndex, level, e.async.lastSeq())
+}
+
+func (e *excelizeam) SetRowOutlineLevelAsync(rowIndex int, level int) {
+	e.async.Go(func(seq uint64) error {
+		return e.setRowOutlineLevel("SetRowOutlineLevelAsync", rowIndex, level, seq)
+	})
+}
+
+func (e *excelizeam) setRowOutlineLevel(op string, rowIndex int, level int, seq uint64) error {
+	if level < 0 || level > 7 {
+		return &RowError{Op: op, Row: rowIndex, Err: excelize.ErrOutlineLevel}
+	}
+	return e.setRowOption(op, rowIndex, seq, rowFieldOutlineLevel, func(opts *excelize.RowOpts) {
+		opts.OutlineLevel = level
+	})
+}
+
+func (e *excelizeam) SetRowStyle(rowIndex int, style excelize.Style) error {
+	if err := e.async.Wait(); err != nil {
+		return err
+	}
+	return e.setRowStyle("SetRowStyle", rowIndex, style, e.async.lastSeq())
+}
+
+func (e *excelizeam) SetRowStyleAsync(rowIndex int, style excelize.Style) {
+	e.async.Go(func(seq uint64) error {
+		return e.setRowStyle("SetRowStyleAsync", rowIndex, style, seq)
+	})
+}
+
+func (e *excelizeam) setRowStyle(op string, rowIndex int, style excelize.Style, seq uint64) error {
+	styleID, err := e.wb.getStyleID(&style)
+	if err != nil {
+		return &RowError{Op: op, Row: rowIndex, Err: err}
+	}
+	return e.setRowOption(op, rowIndex, seq, rowFieldStyle, func(opts *excelize.RowOpts) {
+		opts.StyleID = styleID
+	})
+}
+
+// setRowOption Set the field of the row options with set unless a later operation has set it already
+func (e *excelizeam) setRowOption(op string, rowIndex int, seq uint64, field rowField, set func(opts *excelize.RowOpts)) error {
+	if _, err := excelize.CoordinatesToCellName(1, rowIndex); err != nil {
+		return &RowError{Op: op, Row: rowIndex, Err: err}
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.finishedErr != nil {
+		return &RowError{Op: op, Row: rowIndex, Err: e.finishedErr}
+	}
+	if rowIndex <= e.flushedRow {
+		return &RowError{Op: op, Row: rowIndex, Err: &RowFlushedError{RowIndex: rowIndex, FlushedRowIndex: e.flushedRow}}
+	}
+	if e.maxRow < rowIndex {
+		e.maxRow = rowIndex
+	}
+	if e.rowOptions == nil {
+		e.rowOptions = make(map[int]*rowOptions)
+	}
+	ro, ok := e.rowOptions[rowIndex]
+	if !ok {
+		ro = new(rowOptions)
+		e.rowOptions[rowIndex] = ro
+	}
+	if seq < ro.seqs[field] {
+		return nil
+	}
+	set(&ro.opts)
+	ro.seqs[field] = seq
+	return nil
+}
@@ -0,0 +1,143 @@
+package excelizeam
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type StoredStyle struct {
+	StyleID int
+	Style   *excelize.Style
+}
+
+// styleStore Interned styles of a workbook
+// Styles are indexed both by their canonical key and by their style ID.
+type styleStore struct {
+	mu    sync.RWMutex
+	byKey map[[sha1.Size]byte]StoredStyle
+	byID  map[int]*excelize.Style
+}
+
+// LoadOrStore Get the style ID stored for the key, or store the style made by create
+func (s *styleStore) LoadOrStore(key [sha1.Size]byte, create func() (StoredStyle, error)) (int, error) {
+	s.mu.RLock()
+	stored, ok := s.byKey[key]
+	s.mu.RUnlock()
+	if ok {
+		return stored.StyleID, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if stored, ok = s.byKey[key]; ok {
+		return stored.StyleID, nil
+	}
+	stored, err := create()
+	if err != nil {
+		return 0, err
+	}
+	if s.byKey == nil {
+		s.byKey = make(map[[sha1.Size]byte]StoredStyle)
+		s.byID = make(map[int]*excelize.Style)
+	}
+	s.byKey[key] = stored
+	s.byID[stored.StyleID] = stored.Style
+	return stored.StyleID, nil
+}
+
+// Store Store the style registered in the file beforehand
+// The key keeps pointing to the style stored first, while the style ID always gets the style.
+func (s *styleStore) Store(key [sha1.Size]byte, stored StoredStyle) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.byKey == nil {
+		s.byKey = make(map[[sha1.Size]byte]StoredStyle)
+		s.byID = make(map[int]*excelize.Style)
+	}
+	if _, ok := s.byKey[key]; !ok {
+		s.byKey[key] = stored
+	}
+	s.byID[stored.StyleID] = stored.Style
+}
+
+// Load Get the style stored with the style ID
+// The returned style is shared with the store and must not be modified.
+func (s *styleStore) Load(styleID int) (*excelize.Style, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	style, ok := s.byID[styleID]
+	return style, ok
+}
+
+// copyStyle Get a deep copy of the style
+// Interned styles are compared by value, so they must not share anything the caller may change later.
+func copyStyle(style *excelize.Style) *excelize.Style {
+	styl := *style
+	if style.Border != nil {
+		styl.Border = append([]excelize.Border(nil), style.Border...)
+	}
+	if style.Fill.Color != nil {
+		styl.Fill.Color = append([]string(nil), style.Fill.Color...)
+	}
+	if style.Font != nil {
+		font := *style.Font
+		if font.ColorTheme != nil {
+			colorTheme := *font.ColorTheme
+			font.ColorTheme = &colorTheme
+		}
+		if font.Charset != nil {
+			charset := *font.Charset
+			font.Charset = &charset
+		}
+		styl.Font = &font
+	}
+	if style.Alignment != nil {
+		alignment := *style.Alignment
+		styl.Alignment = &alignment
+	}
+	if style.Protection != nil {
+		protection := *style.Protection
+		styl.Protection = &protection
+	}
+	if style.DecimalPlaces != nil {
+		decimalPlaces := *style.DecimalPlaces
+		styl.DecimalPlaces = &decimalPlaces
+	}
+	if style.CustomNumFmt != nil {
+		customNumFmt := *style.CustomNumFmt
+		styl.CustomNumFmt = &customNumFmt
+	}
+	return &styl
+}
+
+var styleKeyBufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// styleKey Make a canonical key of the style from its values
+// Pointer fields are compared by the values they point to and borders by their position,
+// so that equal styles built separately always share the same key.
+func styleKey(style *excelize.Style) ([sha1.Size]byte, error) {
+	styl := *style
+	if len(styl.Border) > 1 {
+		borders := make([]excelize.Border, len(styl.Border))
+		copy(borders, styl.Border)
+		sort.SliceStable(borders, func(i, j int) bool {
+			return borders[i].Type < borders[j].Type
+		})
+		styl.Border = borders
+	}
+
+	buf := styleKeyBufferPool.Get().(*bytes.Buffer)
+	defer styleKeyBufferPool.Put(buf)
+	buf.Reset()
+	if err := json.NewEncoder(buf).Encode(styl); err != nil {
+		return [sha1.Size]byte{}, err
+	}
+	return sha1.Sum(buf.Bytes()), nil
+}
@@ -0,0 +1,84 @@
+package excelizeam
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var (
+	ErrTableAlreadyExists = errors.New("table already exists")
+	ErrTableHeader        = errors.New("table header must be a unique string")
+	ErrTableRange         = errors.New("table range must have a header row and a data row")
+)
+
+// DefaultTableStyleName Built-in table style applied when the style name of the table is empty
+const DefaultTableStyleName = "TableStyleMedium2"
+
+func (e *excelizeam) AddTable(startColIndex, startRowIndex, endColIndex, endRowIndex int, opts *excelize.Table) error {
+	if err := e.async.Wait(); err != nil {
+		return err
+	}
+	if startColIndex > endColIndex {
+		startColIndex, endColIndex = endColIndex, startColIndex
+	}
+	if startRowIndex > endRowIndex {
+		startRowIndex, endRowIndex = endRowIndex, startRowIndex
+	}
+	if startRowIndex == endRowIndex {
+		return ErrTableRange
+	}
+	startCell, err := excelize.CoordinatesToCellName(startColIndex, startRowIndex)
+	if err != nil {
+		return err
+	}
+	endCell, err := excelize.CoordinatesToCellName(endColIndex, endRowIndex)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.finishedErr != nil {
+		return e.finishedErr
+	}
+	if startRowIndex <= e.flushedRow {
+		return newCellError("AddTable", startColIndex, startRowIndex, &RowFlushedError{RowIndex: startRowIndex, FlushedRowIndex: e.flushedRow})
+	}
+	if e.table != nil {
+		return ErrTableAlreadyExists
+	}
+
+	headers := make(map[string]struct{}, endColIndex-startColIndex+1)
+	for colIdx := startColIndex; colIdx <= endColIndex; colIdx++ {
+		cell, _ := e.cellStore.Load(colIdx, startRowIndex)
+		header, ok := cell.Value.(string)
+		if !ok || header == "" {
+			return newCellError("AddTable", colIdx, startRowIndex, ErrTableHeader)
+		}
+		// column names of a table are case-insensitive
+		key := strings.ToLower(header)
+		if _, ok := headers[key]; ok {
+			return newCellError("AddTable", colIdx, startRowIndex, ErrTableHeader)
+		}
+		headers[key] = struct{}{}
+	}
+
+	var table excelize.Table
+	if opts != nil {
+		table = *opts
+	}
+	table.Range = startCell + ":" + endCell
+	if table.StyleName == "" {
+		table.StyleName = DefaultTableStyleName
+	}
+	if e.maxCol < endColIndex {
+		e.maxCol = endColIndex
+	}
+	if e.maxRow < endRowIndex {
+		e.maxRow = endRowIndex
+	}
+	e.table = &table
+	return nil
+}
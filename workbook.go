@@ -0,0 +1,317 @@
+package excelizeam
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var (
+	ErrSheetAlreadyExists = errors.New("sheet already exists")
+	ErrAlreadyWritten     = errors.New("workbook already written")
+	ErrWorkbookClosed     = errors.New("workbook closed")
+)
+
+const defaultSheetName = "Sheet1"
+
+type Workbook interface {
+	// AddSheet Add a new streamed sheet to the workbook
+	// The first sheet added takes over the default "Sheet1" of the new file
+	AddSheet(name string) (Sheet, error)
+
+	// StyleByID Get the style registered with the style ID
+	// The style is a copy, so changing it leaves the registered style as is.
+	StyleByID(styleID int) (*excelize.Style, bool)
+
+	// StyleIDFromCell Get the style ID of the cell in a sheet of the workbook, such as a cell of a template
+	// Setting the style got by StyleByID with the style ID reuses the style ID.
+	StyleIDFromCell(sheet, cell string) (int, error)
+
+	// Wait
+	// Wait for all running asynchronous operations of every sheet to finish
+	Wait() error
+
+	// Write StreamWriter of every sheet
+	// The sheets are flushed on the first call of Write or File, after which the methods changing them return ErrAlreadyWritten.
+	// Calling Write again writes the same workbook.
+	Write(w io.Writer) error
+
+	// File Get the original excelize.File
+	// The sheets are flushed as with Write.
+	File() (*excelize.File, error)
+
+	// Close Release the temporary files held by the excelize.File
+	// Write and File return ErrWorkbookClosed afterwards, and so do the methods changing the sheets.
+	Close() error
+}
+
+type workbook struct {
+	ctx  context.Context
+	file *excelize.File
+	opts options
+	// opened the file is an existing workbook
+	opened bool
+
+	// fileMu guards the changes made to file by the sheets other than through their StreamWriter
+	fileMu sync.Mutex
+	// validationListCols number of columns used in DataValidationListSheetName, guarded by fileMu
+	validationListCols int
+
+	mu     sync.Mutex
+	sheets []*excelizeam
+	// written the sheets have been flushed, flushErr is the error of the flush
+	written  bool
+	flushErr error
+	closed   bool
+
+	styleStore styleStore
+	// dxfStore differential formats of the conditional formats, whose IDs are apart from the cell styles
+	dxfStore styleStore
+}
+
+func NewWorkbook(opts ...Option) (Workbook, error) {
+	return newWorkbook(opts)
+}
+
+// NewWorkbookWithContext Create Workbook bound to ctx
+// Once ctx is done, queued asynchronous operations are skipped and Write returns ctx.Err().
+func NewWorkbookWithContext(ctx context.Context, opts ...Option) (Workbook, error) {
+	return newWorkbook(append(opts, WithContext(ctx)))
+}
+
+func newWorkbook(opts []Option) (*workbook, error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	return initWorkbook(excelize.NewFile(o.excelizeOptions...), false, o)
+}
+
+// NewWorkbookFromFile Create Workbook from the existing workbook at path
+// The sheets and styles of the workbook are left intact, and AddSheet with the name of an existing sheet streams into that sheet.
+func NewWorkbookFromFile(path string, opts ...Option) (Workbook, error) {
+	return openWorkbook(func(o ...excelize.Options) (*excelize.File, error) {
+		return excelize.OpenFile(path, o...)
+	}, opts)
+}
+
+// NewWorkbookFromReader Create Workbook from the existing workbook read from r
+// The sheets and styles of the workbook are left intact, and AddSheet with the name of an existing sheet streams into that sheet.
+func NewWorkbookFromReader(r io.Reader, opts ...Option) (Workbook, error) {
+	return openWorkbook(func(o ...excelize.Options) (*excelize.File, error) {
+		return excelize.OpenReader(r, o...)
+	}, opts)
+}
+
+func openWorkbook(open func(opts ...excelize.Options) (*excelize.File, error), opts []Option) (*workbook, error) {
+	o, err := newOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	f, err := open(o.excelizeOptions...)
+	if err != nil {
+		return nil, err
+	}
+	return initWorkbook(f, true, o)
+}
+
+func initWorkbook(f *excelize.File, opened bool, o options) (*workbook, error) {
+	if o.workbookProps != nil {
+		if err := f.SetWorkbookProps(o.workbookProps); err != nil {
+			return nil, err
+		}
+	}
+	return &workbook{ctx: o.ctx, file: f, opts: o, opened: opened}, nil
+}
+
+func (wb *workbook) AddSheet(name string) (Sheet, error) {
+	return wb.addSheet(name)
+}
+
+func (wb *workbook) addSheet(name string) (*excelizeam, error) {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	if wb.closed {
+		return nil, ErrWorkbookClosed
+	}
+	if wb.written {
+		return nil, ErrAlreadyWritten
+	}
+
+	for _, e := range wb.sheets {
+		if e.Name() == name {
+			return nil, ErrSheetAlreadyExists
+		}
+	}
+	e := &excelizeam{
+		wb:             wb,
+		defaultPolicy:  wb.opts.overridePolicy,
+		csvFormulaMode: wb.opts.csvFormulaMode,
+	}
+	var exists bool
+	if len(wb.sheets) == 0 && !wb.opened {
+		if err := wb.file.SetSheetName(defaultSheetName, name); err != nil {
+			return nil, err
+		}
+	} else if idx, err := wb.file.GetSheetIndex(name); err != nil {
+		return nil, err
+	} else if idx != -1 {
+		if !wb.opened {
+			return nil, ErrSheetAlreadyExists
+		}
+		exists = true
+	} else if _, err := wb.file.NewSheet(name); err != nil {
+		return nil, err
+	}
+	sw, err := wb.file.NewStreamWriter(name)
+	if err != nil {
+		return nil, err
+	}
+	e.sw = sw
+	e.async = newAsyncQueue(wb.ctx, wb.opts.asyncWorkers, wb.opts.asyncBatchSize, e.applyPendingUpdates)
+	e.async.setErrorLimit(wb.opts.asyncErrorLimit)
+	if exists {
+		// the existing sheet is written again through the StreamWriter, which starts the sheet over
+		if err := e.Import(wb.file, name); err != nil {
+			return nil, err
+		}
+	}
+	if wb.opts.defaultBorder != nil {
+		if err := e.SetDefaultBorderStyle(wb.opts.defaultBorder.Style, wb.opts.defaultBorder.Color); err != nil {
+			return nil, err
+		}
+	}
+	if err := e.SetDefaultBorderColumns(wb.opts.defaultBorderCols); err != nil {
+		return nil, err
+	}
+	wb.sheets = append(wb.sheets, e)
+	return e, nil
+}
+
+func (wb *workbook) StyleByID(styleID int) (*excelize.Style, bool) {
+	style, ok := wb.styleStore.Load(styleID)
+	if !ok {
+		return nil, false
+	}
+	return copyStyle(style), true
+}
+
+func (wb *workbook) StyleIDFromCell(sheet, cell string) (int, error) {
+	styleID, err := wb.file.GetCellStyle(sheet, cell)
+	if err != nil {
+		return 0, err
+	}
+	return wb.importStyle(wb.file, styleID)
+}
+
+func (wb *workbook) Wait() error {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+
+	for _, e := range wb.sheets {
+		if err := e.Wait(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (wb *workbook) Write(w io.Writer) error {
+	if err := wb.flush(); err != nil {
+		return err
+	}
+	if err := wb.file.Write(&contextWriter{ctx: wb.ctx, w: w}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (wb *workbook) File() (*excelize.File, error) {
+	if err := wb.flush(); err != nil {
+		return nil, err
+	}
+	return wb.file, nil
+}
+
+// flush Flush the StreamWriter of every sheet once
+// The StreamWriters cannot be written again, so the following calls return the result of the first one.
+func (wb *workbook) flush() error {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	if wb.closed {
+		return ErrWorkbookClosed
+	}
+	if wb.written {
+		return wb.flushErr
+	}
+	if err := wb.ctx.Err(); err != nil {
+		return err
+	}
+
+	wb.written = true
+	// arrayFormulas refs of the array formulas of the sheets keyed by worksheet path
+	arrayFormulas := make(map[string]map[string]string)
+	for _, e := range wb.sheets {
+		if err := e.writeStream(); err != nil {
+			wb.flushErr = err
+			break
+		}
+		var paths []string
+		if len(e.arrayFormulas) > 0 {
+			paths = sheetPaths(wb.file)
+		}
+		if err := e.sw.Flush(); err != nil {
+			wb.flushErr = err
+			break
+		}
+		if len(e.arrayFormulas) > 0 {
+			path, err := streamedSheetPath(wb.file, paths)
+			if err != nil {
+				wb.flushErr = err
+				break
+			}
+			arrayFormulas[path] = e.arrayFormulas
+		}
+	}
+	if wb.flushErr == nil && len(arrayFormulas) > 0 {
+		zipWriter := wb.file.ZipWriter
+		wb.file.SetZipWriter(func(w io.Writer) excelize.ZipWriter {
+			return &arrayFormulaZipWriter{ZipWriter: zipWriter(w), arrayFormulas: arrayFormulas}
+		})
+	}
+	if wb.flushErr != nil {
+		for _, e := range wb.sheets {
+			e.finish(ErrAlreadyWritten)
+		}
+	}
+	return wb.flushErr
+}
+
+func (wb *workbook) Close() error {
+	wb.mu.Lock()
+	defer wb.mu.Unlock()
+	if wb.closed {
+		return nil
+	}
+	wb.closed = true
+	for _, e := range wb.sheets {
+		e.finish(ErrWorkbookClosed)
+	}
+	return wb.file.Close()
+}
+
+// contextWriter io.Writer which stops writing once ctx is done
+type contextWriter struct {
+	ctx context.Context
+	w   io.Writer
+}
+
+func (cw *contextWriter) Write(p []byte) (int, error) {
+	if err := cw.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cw.w.Write(p)
+}
@@ -0,0 +1,117 @@
+package excelizeam_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"gotest.tools/assert"
+
+	"github.com/tomtwinkle/excelizeam"
+	"github.com/tomtwinkle/excelizeam/excelizestyle"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestWorkbook(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		sheetNames []string
+		testFunc   func(sheets []excelizeam.Sheet) error
+		wantErr    error
+	}{
+		"AddSheet-single": {
+			sheetNames: []string{"test"},
+			testFunc: func(sheets []excelizeam.Sheet) error {
+				return sheets[0].SetCellValue(1, 1, "test", nil, false, false)
+			},
+		},
+		"AddSheet-multiple": {
+			sheetNames: []string{"test1", "test2", "test3"},
+			testFunc: func(sheets []excelizeam.Sheet) error {
+				for i, sheet := range sheets {
+					if err := sheet.SetDefaultBorderStyle(excelizestyle.BorderStyleContinuous1, excelizestyle.BorderColorWhite); err != nil {
+						return err
+					}
+					for rowIdx := 1; rowIdx <= 5; rowIdx++ {
+						sheet.SetCellValueAsync(i+1, rowIdx, fmt.Sprintf("%s-%d", sheet.Name(), rowIdx), &excelize.Style{
+							Border: excelizestyle.BorderAround(excelizestyle.BorderStyleContinuous2, excelizestyle.BorderColorBlack),
+						}, false)
+					}
+				}
+				return nil
+			},
+		},
+		"AddSheet-duplicate_error": {
+			sheetNames: []string{"test", "test"},
+			wantErr:    excelizeam.ErrSheetAlreadyExists,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			wb, err := excelizeam.NewWorkbook()
+			assert.NilError(t, err)
+			sheets := make([]excelizeam.Sheet, 0, len(tt.sheetNames))
+			for _, sheetName := range tt.sheetNames {
+				var sheet excelizeam.Sheet
+				sheet, err = wb.AddSheet(sheetName)
+				if err != nil {
+					break
+				}
+				sheets = append(sheets, sheet)
+			}
+			if tt.wantErr != nil {
+				assert.ErrorContains(t, err, tt.wantErr.Error())
+				return
+			}
+			assert.NilError(t, err)
+			if tt.testFunc != nil {
+				assert.NilError(t, tt.testFunc(sheets))
+			}
+			var buf bytes.Buffer
+			err = wb.Write(&buf)
+			assert.NilError(t, err)
+
+			actual, err := excelize.OpenReader(&buf)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tt.sheetNames, actual.GetSheetList())
+			for _, sheet := range sheets {
+				expected, err := sheet.CSVRecords()
+				assert.NilError(t, err)
+				rows, err := actual.GetRows(sheet.Name())
+				assert.NilError(t, err)
+				for rowIdx, row := range expected {
+					for colIdx, value := range row {
+						if value == "" {
+							continue
+						}
+						assert.Equal(t, value, rows[rowIdx][colIdx])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestWorkbook_Close(t *testing.T) {
+	t.Parallel()
+	wb, err := excelizeam.NewWorkbook()
+	assert.NilError(t, err)
+	sheet, err := wb.AddSheet("test")
+	assert.NilError(t, err)
+	assert.NilError(t, sheet.SetCellValue(1, 1, "test", nil, false, false))
+	var buf bytes.Buffer
+	assert.NilError(t, wb.Write(&buf))
+
+	_, err = wb.AddSheet("after")
+	assert.Assert(t, errors.Is(err, excelizeam.ErrAlreadyWritten))
+	assert.NilError(t, wb.Write(&buf))
+
+	assert.NilError(t, wb.Close())
+	_, err = wb.AddSheet("after")
+	assert.Assert(t, errors.Is(err, excelizeam.ErrWorkbookClosed))
+	_, err = wb.File()
+	assert.Assert(t, errors.Is(err, excelizeam.ErrWorkbookClosed))
+}